@@ -0,0 +1,113 @@
+package certificate
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Reloader Serves a certificate loaded from disk, picking up changes to the files (e.g. renewals) without a restart
+type Reloader struct {
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	// modified Latest modification time of the files as of the last successful load
+	modified time.Time
+}
+
+func NewReloader(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetCertificate Returns the current certificate, intended to be used as tls.Config.GetCertificate
+func (r *Reloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// Run Checks the files for changes on every interval until the context is cancelled. If changed files fail to load
+// (e.g. because only one of them has been replaced yet), the previous certificate keeps being served and loading is
+// retried on the next check.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := r.reload()
+			if err != nil {
+				log.Ctx(ctx).Error().
+					Err(err).
+					Str("cert", r.certFile).
+					Msg("Failed to reload TLS certificate")
+				continue
+			}
+			if reloaded {
+				log.Ctx(ctx).Info().
+					Str("cert", r.certFile).
+					Msg("Reloaded TLS certificate")
+			}
+		}
+	}
+}
+
+// reload Loads the certificate if either file was modified since the last load
+func (r *Reloader) reload() (bool, error) {
+	modified, err := r.lastModified()
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	unchanged := r.cert != nil && !modified.After(r.modified)
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to load key pair: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.modified = modified
+
+	return true, nil
+}
+
+func (r *Reloader) lastModified() (time.Time, error) {
+	var latest time.Time
+	for _, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to stat %s: %w", name, err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -1,13 +1,88 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Database DatabaseConfig `yaml:"db"`
+	// RealmConfig The only realm served if no (additional) realms are configured
+	RealmConfig `yaml:",inline"`
+	// Realms Independent realms (communities) to serve from the same process, each with their own database. If any
+	// realms are configured, the top-level realm sections are ignored.
+	Realms []RealmConfig `yaml:"realms"`
+	// HTTP Applies to the listener and every realm
+	HTTP HTTPConfig `yaml:"http"`
+}
+
+type HTTPConfig struct {
+	TLS     TLSConfig     `yaml:"tls"`
+	Proxies ProxiesConfig `yaml:"proxies"`
+	CORS    CORSConfig    `yaml:"cors"`
+	Gzip    GzipConfig    `yaml:"gzip"`
+	// Timeout Time to handle a request in (not applied to the event stream), defaults to 10 seconds
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// TLSConfig HTTPS is served instead of HTTP if a certificate is configured
+type TLSConfig struct {
+	CertFile string `yaml:"certfile"`
+	KeyFile  string `yaml:"keyfile"`
+	// Reload Interval to check the certificate and key for changes (e.g. renewals) on, defaults to 1 minute
+	Reload time.Duration `yaml:"reload"`
+}
+
+// ProxiesConfig Reverse proxies in front of gasp, whose forwarded client addresses are used for logging, rate limiting
+// and server authorization. Forwarded addresses are ignored unless sent by a trusted proxy.
+type ProxiesConfig struct {
+	// Trusted IP addresses or networks (CIDR notation) of the proxies
+	Trusted []string `yaml:"trusted"`
+	// Header Header the proxies forward the client address in, either "x-forwarded-for" (default) or "x-real-ip"
+	Header string `yaml:"header"`
+}
+
+// CORSConfig Cross-origin access to the JSON API (/api/) and OpenAPI document, e.g. for browser-based frontends
+type CORSConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// AllowOrigins Origins allowed to access the API (e.g. "https://stats.example.com"), defaults to any origin ("*")
+	AllowOrigins []string `yaml:"alloworigins"`
+	// MaxAge Time browsers may cache preflight responses for, not cached by default
+	MaxAge time.Duration `yaml:"maxage"`
+}
+
+// GzipConfig Compression of responses for clients which accept it (not applied to the event stream)
+type GzipConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Level Compression level from 1 (fastest) to 9 (smallest), defaults to the standard library's default level
+	Level int `yaml:"level"`
+	// MinLength Size (in bytes) below which responses are not compressed, defaults to 1024
+	MinLength int `yaml:"minlength"`
+}
+
+type RealmConfig struct {
+	// Name Included in logs, defaults to "default" for the top-level realm
+	Name string `yaml:"name"`
+	// Hosts Hostnames to serve the realm on, any hostname if empty
+	Hosts []string `yaml:"hosts"`
+	// Prefix Path prefix to serve the realm under (e.g. "/mod"), which is stripped before routing
+	Prefix    string          `yaml:"prefix"`
+	Database  DatabaseConfig  `yaml:"db"`
+	RateLimit RateLimitConfig `yaml:"ratelimit"`
+	Cache     CacheConfig     `yaml:"cache"`
+	Jobs      JobsConfig      `yaml:"jobs"`
+	Seasons   SeasonsConfig   `yaml:"seasons"`
+	Unlocks   UnlocksConfig   `yaml:"unlocks"`
+	Webhooks  WebhooksConfig  `yaml:"webhooks"`
+	Stream    StreamConfig    `yaml:"stream"`
+	Admin     AdminConfig     `yaml:"admin"`
+	// Catalogue Path to the mod's catalogue definition file, defaults to the built-in vanilla BF2 catalogue
+	Catalogue string `yaml:"catalogue"`
 }
 
 type DatabaseConfig struct {
@@ -15,6 +90,149 @@ type DatabaseConfig struct {
 	DatabaseName string `yaml:"dbname"`
 	Username     string `yaml:"user"`
 	Password     string `yaml:"passwd"`
+	// Replicas Read replicas of the (primary) database to run stats queries on, writes always go to the primary
+	Replicas []ReplicaConfig `yaml:"replicas"`
+	// MaxLag Replicas lagging further behind are not queried until they catch up, defaults to 10 seconds
+	MaxLag time.Duration `yaml:"maxlag"`
+	// LagCheck Interval to check the replicas' lag on, defaults to 5 seconds
+	LagCheck time.Duration `yaml:"lagcheck"`
+}
+
+// ReplicaConfig Database name is always the primary's. Credentials default to the primary's, but the user needs the
+// REPLICATION CLIENT privilege for lag checks.
+type ReplicaConfig struct {
+	Host     string `yaml:"host"`
+	Username string `yaml:"user"`
+	Password string `yaml:"passwd"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Default Applies to any endpoint without a specific limit, defaults to 10 requests per second with a burst of 20
+	Default LimitConfig `yaml:"default"`
+	// Endpoints Endpoint specific limits, keyed by endpoint name (e.g. "getleaderboard"). Any values not set are taken
+	// from the default limit. The (expensive) player search ("playersearch") is limited to 1 request per second with a
+	// burst of 5 unless configured otherwise.
+	Endpoints map[string]LimitConfig `yaml:"endpoints"`
+	// Allowlist IP addresses or networks (CIDR notation) to never limit
+	Allowlist []string `yaml:"allowlist"`
+	// AllowServers Never limit registered servers
+	AllowServers bool `yaml:"allowservers"`
+}
+
+type LimitConfig struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+	// ExpiresIn Duration after which an idle client's limiter is removed, defaults to 3 minutes
+	ExpiresIn time.Duration `yaml:"expiresin"`
+}
+
+type CacheConfig struct {
+	// Leaderboard Size defaults to 1000 entries, TTL to 1 minute
+	Leaderboard StoreConfig `yaml:"leaderboard"`
+	// PlayerInfo Size defaults to 10000 entries, TTL to 5 minutes
+	PlayerInfo StoreConfig `yaml:"playerinfo"`
+}
+
+type StoreConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Size Maximum number of entries, after which the least recently used entries are evicted
+	Size int `yaml:"size"`
+	// TTL Time after which entries expire
+	TTL time.Duration `yaml:"ttl"`
+}
+
+type JobsConfig struct {
+	Snapshot   SnapshotConfig   `yaml:"snapshot"`
+	RisingStar RisingStarConfig `yaml:"risingstar"`
+}
+
+// JobConfig Schedule is a standard cron spec or descriptor, e.g. "0 3 * * 1", "@weekly" or "@every 5m"
+type JobConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Schedule string `yaml:"schedule"`
+}
+
+type SnapshotConfig struct {
+	JobConfig `yaml:",inline"`
+}
+
+type RisingStarConfig struct {
+	JobConfig `yaml:",inline"`
+	// Window Period to compute score gains over, defaults to a week
+	Window time.Duration `yaml:"window"`
+}
+
+type SeasonsConfig struct {
+	// SchemaPrefix Prefix of the schemas holding archived seasons, defaults to "<dbname>_season_"
+	SchemaPrefix string `yaml:"schemaprefix"`
+	// Hosts Hostnames to serve an archived season's stats on, mapped to the season's id
+	Hosts map[string]uint32 `yaml:"hosts"`
+}
+
+// UnlocksConfig Unlock point policy, pools which are not configured use the original backend's defaults
+// (14 enlisted kit unlocks, no officer unlocks)
+type UnlocksConfig struct {
+	Enlisted *UnlockPoolConfig `yaml:"enlisted"`
+	Officer  *UnlockPoolConfig `yaml:"officer"`
+	// Respecs Number of free respecs (revoking an unlock to get the point back) granted to each player by default
+	Respecs uint16 `yaml:"respecs"`
+}
+
+type UnlockPoolConfig struct {
+	// Unlocks IDs of the unlocks the pool's points can be spent on
+	Unlocks []uint16 `yaml:"unlocks"`
+	// Ranks Points granted upon reaching a rank, keyed by rank id
+	Ranks map[uint8]int `yaml:"ranks"`
+	// Badges Points granted for earning a badge at a level
+	Badges []BadgeGrantConfig `yaml:"badges"`
+}
+
+type BadgeGrantConfig struct {
+	ID     uint32 `yaml:"id"`
+	Level  uint64 `yaml:"level"`
+	Points int    `yaml:"points"`
+}
+
+// WebhooksConfig Events are only recorded and delivered if at least one endpoint is configured
+type WebhooksConfig struct {
+	Endpoints []WebhookConfig `yaml:"endpoints"`
+	// Deliver Schedule to deliver queued events on, defaults to "@every 10s"
+	Deliver string `yaml:"deliver"`
+	// Watch Schedule to check for rank changes, awards, bans and top 10 changes on (also used for the event stream),
+	// defaults to "@every 1m"
+	Watch string `yaml:"watch"`
+	// MaxAttempts Number of attempts after which a delivery is given up on, defaults to 10
+	MaxAttempts uint16 `yaml:"maxattempts"`
+	// Backoff Delay before the first retry (doubling with every further retry), defaults to 30 seconds
+	Backoff time.Duration `yaml:"backoff"`
+	// MaxBackoff Upper bound for the delay between retries, defaults to 6 hours
+	MaxBackoff time.Duration `yaml:"maxbackoff"`
+	// Timeout Time to wait for an endpoint to respond, defaults to 10 seconds
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// StreamConfig Server-sent events stream of live updates (/api/v1/events)
+type StreamConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Buffer Number of events to buffer per client before dropping events, defaults to 64
+	Buffer int `yaml:"buffer"`
+}
+
+// AdminConfig Access to the administrative endpoints (/admin/), which are inaccessible unless keys are configured
+type AdminConfig struct {
+	// Keys Secrets granting admin access, any of which needs to be sent in the X-Admin-Key header
+	Keys []string `yaml:"keys"`
+}
+
+type WebhookConfig struct {
+	// Name Identifies the endpoint in the outbox, renaming it drops any pending deliveries
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+	// Secret Key to sign payloads with (HMAC-SHA256), payloads are not signed if empty
+	Secret string `yaml:"secret"`
+	// Events Types of events to deliver (e.g. "rank.changed"), all types if empty
+	Events []string `yaml:"events"`
 }
 
 func LoadConfig(path string) (Config, error) {
@@ -29,5 +247,191 @@ func LoadConfig(path string) (Config, error) {
 		return Config{}, err
 	}
 
+	if err = setHTTPDefaults(&config.HTTP); err != nil {
+		return Config{}, err
+	}
+
+	if len(config.Realms) == 0 {
+		if config.Name == "" {
+			config.Name = "default"
+		}
+		config.Realms = []RealmConfig{config.RealmConfig}
+	}
+
+	names := make(map[string]bool, len(config.Realms))
+	for i := range config.Realms {
+		realm := &config.Realms[i]
+		if realm.Name == "" {
+			return Config{}, fmt.Errorf("realm %d is missing a name", i)
+		}
+		if names[realm.Name] {
+			return Config{}, fmt.Errorf("duplicate realm name: %s", realm.Name)
+		}
+		names[realm.Name] = true
+
+		if realm.Prefix != "" && (!strings.HasPrefix(realm.Prefix, "/") || strings.HasSuffix(realm.Prefix, "/")) {
+			return Config{}, fmt.Errorf("invalid prefix for realm %s (must start but not end with a slash): %s", realm.Name, realm.Prefix)
+		}
+
+		if realm.Seasons.SchemaPrefix == "" {
+			realm.Seasons.SchemaPrefix = realm.Database.DatabaseName + "_season_"
+		}
+
+		if slices.Contains(realm.Admin.Keys, "") {
+			return Config{}, fmt.Errorf("empty admin key for realm %s", realm.Name)
+		}
+
+		setDatabaseDefaults(&realm.Database)
+		if err = setRateLimitDefaults(&realm.RateLimit); err != nil {
+			return Config{}, fmt.Errorf("invalid rate limit for realm %s: %w", realm.Name, err)
+		}
+		if err = setStoreDefaults(&realm.Cache.Leaderboard, 1000, time.Minute); err != nil {
+			return Config{}, fmt.Errorf("invalid leaderboard cache for realm %s: %w", realm.Name, err)
+		}
+		if err = setStoreDefaults(&realm.Cache.PlayerInfo, 10000, time.Minute*5); err != nil {
+			return Config{}, fmt.Errorf("invalid playerinfo cache for realm %s: %w", realm.Name, err)
+		}
+		switch {
+		case realm.Jobs.RisingStar.Window == 0:
+			realm.Jobs.RisingStar.Window = time.Hour * 24 * 7
+		case realm.Jobs.RisingStar.Window < 0:
+			return Config{}, fmt.Errorf("invalid risingstar window for realm %s (must be positive): %s", realm.Name, realm.Jobs.RisingStar.Window)
+		}
+		setWebhooksDefaults(&realm.Webhooks)
+		if realm.Stream.Buffer == 0 {
+			realm.Stream.Buffer = 64
+		}
+	}
+
 	return config, nil
 }
+
+func setHTTPDefaults(cfg *HTTPConfig) error {
+	if (cfg.TLS.CertFile == "") != (cfg.TLS.KeyFile == "") {
+		return errors.New("tls requires both a certificate and a key file")
+	}
+	if cfg.TLS.Reload == 0 {
+		cfg.TLS.Reload = time.Minute
+	}
+
+	switch cfg.Proxies.Header {
+	case "":
+		cfg.Proxies.Header = "x-forwarded-for"
+	case "x-forwarded-for", "x-real-ip":
+	default:
+		return fmt.Errorf("invalid proxies header (must be x-forwarded-for or x-real-ip): %s", cfg.Proxies.Header)
+	}
+
+	if len(cfg.CORS.AllowOrigins) == 0 {
+		cfg.CORS.AllowOrigins = []string{"*"}
+	}
+
+	if cfg.Gzip.Level < 0 || cfg.Gzip.Level > 9 {
+		return fmt.Errorf("invalid gzip level (must be between 1 and 9): %d", cfg.Gzip.Level)
+	}
+	if cfg.Gzip.MinLength == 0 {
+		cfg.Gzip.MinLength = 1024
+	}
+
+	if cfg.Timeout == 0 {
+		cfg.Timeout = time.Second * 10
+	}
+
+	return nil
+}
+
+func setDatabaseDefaults(cfg *DatabaseConfig) {
+	for i := range cfg.Replicas {
+		replica := &cfg.Replicas[i]
+		if replica.Username == "" {
+			replica.Username = cfg.Username
+		}
+		if replica.Password == "" {
+			replica.Password = cfg.Password
+		}
+	}
+	if cfg.MaxLag == 0 {
+		cfg.MaxLag = time.Second * 10
+	}
+	if cfg.LagCheck == 0 {
+		cfg.LagCheck = time.Second * 5
+	}
+}
+
+func setRateLimitDefaults(cfg *RateLimitConfig) error {
+	// Zero values would deny every request, so any values not set need to be defaulted
+	if err := setLimitDefaults(&cfg.Default, LimitConfig{Rate: 10, Burst: 20, ExpiresIn: time.Minute * 3}); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+
+	if cfg.Endpoints == nil {
+		cfg.Endpoints = make(map[string]LimitConfig)
+	}
+	if _, ok := cfg.Endpoints["playersearch"]; !ok {
+		cfg.Endpoints["playersearch"] = LimitConfig{Rate: 1, Burst: 5}
+	}
+
+	for endpoint, limit := range cfg.Endpoints {
+		if err := setLimitDefaults(&limit, cfg.Default); err != nil {
+			return fmt.Errorf("%s: %w", endpoint, err)
+		}
+		cfg.Endpoints[endpoint] = limit
+	}
+
+	return nil
+}
+
+func setLimitDefaults(cfg *LimitConfig, defaults LimitConfig) error {
+	if cfg.Rate < 0 || cfg.Burst < 0 || cfg.ExpiresIn < 0 {
+		return errors.New("rate, burst and expiresin must not be negative")
+	}
+
+	if cfg.Rate == 0 {
+		cfg.Rate = defaults.Rate
+	}
+	if cfg.Burst == 0 {
+		cfg.Burst = defaults.Burst
+	}
+	if cfg.ExpiresIn == 0 {
+		cfg.ExpiresIn = defaults.ExpiresIn
+	}
+
+	return nil
+}
+
+func setStoreDefaults(cfg *StoreConfig, size int, ttl time.Duration) error {
+	if cfg.Size < 0 || cfg.TTL < 0 {
+		return errors.New("size and ttl must not be negative")
+	}
+
+	// Zero values would not cache anything (entries would be evicted or expire right away)
+	if cfg.Size == 0 {
+		cfg.Size = size
+	}
+	if cfg.TTL == 0 {
+		cfg.TTL = ttl
+	}
+
+	return nil
+}
+
+func setWebhooksDefaults(cfg *WebhooksConfig) {
+	if cfg.Deliver == "" {
+		cfg.Deliver = "@every 10s"
+	}
+	if cfg.Watch == "" {
+		cfg.Watch = "@every 1m"
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = 10
+	}
+	if cfg.Backoff == 0 {
+		cfg.Backoff = time.Second * 30
+	}
+	if cfg.MaxBackoff == 0 {
+		cfg.MaxBackoff = time.Hour * 6
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = time.Second * 10
+	}
+}
@@ -0,0 +1,51 @@
+package listjobs
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cetteup/gasp/internal/schedule"
+)
+
+type Scheduler interface {
+	Statuses() []schedule.Status
+}
+
+type Handler struct {
+	scheduler Scheduler
+}
+
+func NewHandler(scheduler Scheduler) *Handler {
+	return &Handler{
+		scheduler: scheduler,
+	}
+}
+
+type jobDTO struct {
+	Name         string `json:"name"`
+	Spec         string `json:"spec"`
+	Running      bool   `json:"running"`
+	LastRun      uint32 `json:"lastRun"`
+	LastDuration uint32 `json:"lastDuration"`
+	LastError    string `json:"lastError"`
+	NextRun      uint32 `json:"nextRun"`
+}
+
+func (h *Handler) HandleGET(c echo.Context) error {
+	statuses := h.scheduler.Statuses()
+	dtos := make([]jobDTO, 0, len(statuses))
+	for _, status := range statuses {
+		dtos = append(dtos, jobDTO{
+			Name:         status.Name,
+			Spec:         status.Spec,
+			Running:      status.Running,
+			LastRun:      status.LastRun,
+			LastDuration: status.LastDuration,
+			LastError:    status.LastError,
+			NextRun:      status.NextRun,
+		})
+	}
+
+	return c.JSON(http.StatusOK, dtos)
+}
@@ -0,0 +1,204 @@
+package manageclans
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/domain/clan"
+	"github.com/cetteup/gasp/internal/domain/player"
+)
+
+type Handler struct {
+	playerRepository player.Repository
+	clanRepository   clan.Repository
+}
+
+func NewHandler(playerRepository player.Repository, clanRepository clan.Repository) *Handler {
+	return &Handler{
+		playerRepository: playerRepository,
+		clanRepository:   clanRepository,
+	}
+}
+
+type clanDTO struct {
+	ID      uint32 `json:"id"`
+	Tag     string `json:"tag"`
+	Name    string `json:"name"`
+	Created uint32 `json:"created"`
+}
+
+type memberDTO struct {
+	ClanID uint32 `json:"clanId"`
+	PID    uint32 `json:"pid"`
+	Role   string `json:"role"`
+}
+
+// HandlePOST Creates a clan (without any members)
+func (h *Handler) HandlePOST(c echo.Context) error {
+	params := struct {
+		Tag  string `json:"tag" form:"tag" validate:"required,max=6"`
+		Name string `json:"name" form:"name" validate:"required,max=64"`
+	}{}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	cl := clan.Clan{
+		Tag:  params.Tag,
+		Name: params.Name,
+		// Will overflow on 7 February 2106 at 06:28:15 UTC
+		Created: uint32(time.Now().UTC().Unix()),
+	}
+
+	id, err := h.clanRepository.Insert(c.Request().Context(), cl)
+	if err != nil {
+		if errors.Is(err, clan.ErrDuplicateTag) {
+			return echo.NewHTTPError(http.StatusConflict).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to insert clan: %w", err))
+	}
+
+	return c.JSON(http.StatusCreated, clanDTO{
+		ID:      id,
+		Tag:     cl.Tag,
+		Name:    cl.Name,
+		Created: cl.Created,
+	})
+}
+
+// HandlePUT Updates the clan's tag and name
+func (h *Handler) HandlePUT(c echo.Context) error {
+	params := struct {
+		ID   uint32 `param:"id" validate:"required"`
+		Tag  string `json:"tag" form:"tag" validate:"required,max=6"`
+		Name string `json:"name" form:"name" validate:"required,max=64"`
+	}{}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	err := h.clanRepository.Update(c.Request().Context(), clan.Clan{
+		ID:   params.ID,
+		Tag:  params.Tag,
+		Name: params.Name,
+	})
+	if err != nil {
+		if errors.Is(err, clan.ErrClanNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		if errors.Is(err, clan.ErrDuplicateTag) {
+			return echo.NewHTTPError(http.StatusConflict).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to update clan: %w", err))
+	}
+
+	cl, err := h.clanRepository.FindByID(c.Request().Context(), params.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find clan: %w", err))
+	}
+
+	return c.JSON(http.StatusOK, clanDTO{
+		ID:      cl.ID,
+		Tag:     cl.Tag,
+		Name:    cl.Name,
+		Created: cl.Created,
+	})
+}
+
+// HandleDELETE Deletes the clan, removing all of its members (players themselves are not affected)
+func (h *Handler) HandleDELETE(c echo.Context) error {
+	params := struct {
+		ID uint32 `param:"id" validate:"required"`
+	}{}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	if err := h.clanRepository.Delete(c.Request().Context(), params.ID); err != nil {
+		if errors.Is(err, clan.ErrClanNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to delete clan: %w", err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandlePUTMember Adds the player to the clan or changes their role. Players can only be a member of a single clan.
+func (h *Handler) HandlePUTMember(c echo.Context) error {
+	params := struct {
+		ID   uint32 `param:"id" validate:"required"`
+		PID  uint32 `param:"pid" validate:"required"`
+		Role string `json:"role" form:"role" validate:"oneof=member officer leader"`
+	}{
+		// Default values
+		Role: clan.RoleMember.String(),
+	}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	// Role names are validated above, so this cannot fail
+	role, _ := clan.ParseRole(params.Role)
+
+	if _, err := h.playerRepository.FindByID(c.Request().Context(), params.PID); err != nil {
+		if errors.Is(err, player.ErrPlayerNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find player: %w", err))
+	}
+
+	err := h.clanRepository.SetMember(c.Request().Context(), params.ID, clan.Member{
+		Player: clan.PlayerRef{
+			ID: params.PID,
+		},
+		Role: role,
+		// Will overflow on 7 February 2106 at 06:28:15 UTC
+		Joined: uint32(time.Now().UTC().Unix()),
+	})
+	if err != nil {
+		if errors.Is(err, clan.ErrClanNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		if errors.Is(err, clan.ErrAlreadyInAClan) {
+			return echo.NewHTTPError(http.StatusConflict).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to set clan member: %w", err))
+	}
+
+	return c.JSON(http.StatusOK, memberDTO{
+		ClanID: params.ID,
+		PID:    params.PID,
+		Role:   role.String(),
+	})
+}
+
+// HandleDELETEMember Removes the player from the clan
+func (h *Handler) HandleDELETEMember(c echo.Context) error {
+	params := struct {
+		ID  uint32 `param:"id" validate:"required"`
+		PID uint32 `param:"pid" validate:"required"`
+	}{}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	if err := h.clanRepository.RemoveMember(c.Request().Context(), params.ID, params.PID); err != nil {
+		if errors.Is(err, clan.ErrMemberNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to remove clan member: %w", err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,179 @@
+package revokeunlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/cache"
+	"github.com/cetteup/gasp/internal/domain/event"
+	"github.com/cetteup/gasp/internal/domain/player"
+	"github.com/cetteup/gasp/internal/domain/unlock"
+	"github.com/cetteup/gasp/pkg/task"
+)
+
+type Handler struct {
+	playerRepository       player.Repository
+	unlockRepository       unlock.Repository
+	unlockRecordRepository unlock.RecordRepository
+	respecRepository       unlock.RespecRepository
+	defaultRespecs         uint16
+	invalidator            cache.PlayerInvalidator
+	publisher              event.Publisher
+}
+
+func NewHandler(
+	playerRepository player.Repository,
+	unlockRepository unlock.Repository,
+	unlockRecordRepository unlock.RecordRepository,
+	respecRepository unlock.RespecRepository,
+	defaultRespecs uint16,
+	invalidator cache.PlayerInvalidator,
+	publisher event.Publisher,
+) *Handler {
+	return &Handler{
+		playerRepository:       playerRepository,
+		unlockRepository:       unlockRepository,
+		unlockRecordRepository: unlockRecordRepository,
+		respecRepository:       respecRepository,
+		defaultRespecs:         defaultRespecs,
+		invalidator:            invalidator,
+		publisher:              publisher,
+	}
+}
+
+type revokedDTO struct {
+	PID     uint32   `json:"pid"`
+	Revoked []uint16 `json:"revoked"`
+	// Respecs Number of free respecs the player has left
+	Respecs uint16 `json:"respecs"`
+}
+
+// HandleDELETE Revokes a player's unlock, returning the unlock point to the player. Unless forced, doing so uses up one
+// of the player's free respecs. Unlocks requiring the revoked unlock are only revoked along with it if cascade is set.
+func (h *Handler) HandleDELETE(c echo.Context) error {
+	params := struct {
+		PID      uint32 `param:"pid" validate:"required"`
+		UnlockID uint16 `param:"id" validate:"required"`
+		Cascade  bool   `query:"cascade"`
+		Force    bool   `query:"force"`
+	}{}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	var p player.Player
+	var catalogue *unlock.Catalogue
+	var unlockRecords []unlock.Record
+	var respecs unlock.Respecs
+	var runner task.AsyncRunner
+	runner.Append(func(ctx context.Context) error {
+		// Unlock records are returned for any player id, so the player needs to be checked to exist
+		var err2 error
+		p, err2 = h.playerRepository.FindByID(ctx, params.PID)
+		if err2 != nil {
+			return fmt.Errorf("failed to find player: %w", err2)
+		}
+		return nil
+	})
+	runner.Append(func(ctx context.Context) error {
+		unlocks, err2 := h.unlockRepository.FindAll(ctx)
+		if err2 != nil {
+			return fmt.Errorf("failed to find unlocks: %w", err2)
+		}
+		catalogue = unlock.NewCatalogue(unlocks)
+		return nil
+	})
+	runner.Append(func(ctx context.Context) error {
+		var err2 error
+		unlockRecords, err2 = h.unlockRecordRepository.FindByPlayerID(ctx, params.PID)
+		if err2 != nil {
+			return fmt.Errorf("failed to find unlock records: %w", err2)
+		}
+		return nil
+	})
+	runner.Append(func(ctx context.Context) error {
+		var err2 error
+		respecs, err2 = h.respecRepository.FindByPlayerID(ctx, params.PID)
+		if err2 != nil {
+			return fmt.Errorf("failed to find respecs: %w", err2)
+		}
+		return nil
+	})
+
+	if err := runner.Run(c.Request().Context()); err != nil {
+		if errors.Is(err, player.ErrPlayerNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+
+	if !slices.ContainsFunc(unlockRecords, func(record unlock.Record) bool {
+		return record.Unlock.ID == params.UnlockID && record.Unlocked
+	}) {
+		return echo.NewHTTPError(http.StatusNotFound).SetInternal(fmt.Errorf("unlock not unlocked: %d", params.UnlockID))
+	}
+
+	// Revoking an unlock without revoking unlocks requiring it would leave the player in an invalid state
+	revoke := catalogue.FindDependents(params.UnlockID, unlockRecords)
+	if len(revoke) > 0 && !params.Cascade {
+		return echo.NewHTTPError(http.StatusConflict).SetInternal(fmt.Errorf("unlock %d is required by unlocked %v", params.UnlockID, revoke))
+	}
+	revoke = append(revoke, params.UnlockID)
+
+	remaining := respecs.Remaining(h.defaultRespecs)
+	if !params.Force {
+		if remaining < 1 {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity).SetInternal(errors.New("no free respecs left"))
+		}
+		remaining--
+	}
+
+	// Will overflow on 7 February 2106 at 06:28:15 UTC
+	timestamp := uint32(time.Now().UTC().Unix())
+	err := h.unlockRecordRepository.Revoke(c.Request().Context(), params.PID, revoke, timestamp, !params.Force)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to revoke unlock records: %w", err))
+	}
+
+	if err = h.invalidator.InvalidatePlayer(c.Request().Context(), params.PID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to invalidate cached player data: %w", err))
+	}
+
+	events := make([]event.Event, 0, len(revoke))
+	for _, id := range revoke {
+		events = append(events, event.Event{
+			Type: event.TypeUnlockRevoked,
+			Player: event.PlayerRef{
+				ID:   p.ID,
+				Name: p.Name,
+			},
+			Timestamp: timestamp,
+			Unlock: &event.UnlockChange{
+				ID: id,
+			},
+		})
+	}
+
+	// Unlocks have been revoked at this point, so failing to publish should not fail the request
+	if err = h.publisher.Publish(c.Request().Context(), events...); err != nil {
+		log.Ctx(c.Request().Context()).Error().
+			Err(err).
+			Uint32("pid", p.ID).
+			Msg("Failed to publish unlock revoked events")
+	}
+
+	return c.JSON(http.StatusOK, revokedDTO{
+		PID:     params.PID,
+		Revoked: revoke,
+		Respecs: remaining,
+	})
+}
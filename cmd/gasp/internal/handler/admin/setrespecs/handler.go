@@ -0,0 +1,58 @@
+package setrespecs
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/domain/unlock"
+)
+
+type Handler struct {
+	respecRepository unlock.RespecRepository
+	defaultRespecs   uint16
+}
+
+func NewHandler(respecRepository unlock.RespecRepository, defaultRespecs uint16) *Handler {
+	return &Handler{
+		respecRepository: respecRepository,
+		defaultRespecs:   defaultRespecs,
+	}
+}
+
+type respecsDTO struct {
+	PID       uint32 `json:"pid"`
+	Allowance uint16 `json:"allowance"`
+	Used      uint16 `json:"used"`
+	Remaining uint16 `json:"remaining"`
+}
+
+// HandlePUT Sets the number of free respecs granted to the player, overriding the default allowance
+func (h *Handler) HandlePUT(c echo.Context) error {
+	params := struct {
+		PID       uint32 `param:"pid" validate:"required"`
+		Allowance uint16 `json:"allowance" form:"allowance"`
+	}{}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	if err := h.respecRepository.SetAllowance(c.Request().Context(), params.PID, params.Allowance); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to set respec allowance: %w", err))
+	}
+
+	respecs, err := h.respecRepository.FindByPlayerID(c.Request().Context(), params.PID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find respecs: %w", err))
+	}
+
+	return c.JSON(http.StatusOK, respecsDTO{
+		PID:       params.PID,
+		Allowance: params.Allowance,
+		Used:      respecs.Used,
+		Remaining: respecs.Remaining(h.defaultRespecs),
+	})
+}
@@ -0,0 +1,191 @@
+package startseason
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/cache"
+	"github.com/cetteup/gasp/internal/domain/season"
+)
+
+// Handler Starts seasons in the background, since archiving and resetting all stats takes far longer than any request
+// is allowed to take. Only one season can be started at a time.
+type Handler struct {
+	seasonRepository season.Repository
+	seasonArchiver   season.Archiver
+	invalidator      cache.StatsInvalidator
+
+	// starts Passes accepted starts to Run, can hold exactly one start since only one start can be running at a time
+	starts chan start
+
+	mu sync.Mutex
+	// status Status of the most recent start, nil until a season is started
+	status *statusDTO
+}
+
+func NewHandler(seasonRepository season.Repository, seasonArchiver season.Archiver, invalidator cache.StatsInvalidator) *Handler {
+	return &Handler{
+		seasonRepository: seasonRepository,
+		seasonArchiver:   seasonArchiver,
+		invalidator:      invalidator,
+		starts:           make(chan start, 1),
+	}
+}
+
+type start struct {
+	name  string
+	reset bool
+}
+
+type seasonDTO struct {
+	ID    uint32 `json:"id"`
+	Name  string `json:"name"`
+	Start uint32 `json:"start"`
+}
+
+type statusDTO struct {
+	Name      string     `json:"name"`
+	Reset     bool       `json:"reset"`
+	Running   bool       `json:"running"`
+	Requested uint32     `json:"requested"`
+	Finished  uint32     `json:"finished"`
+	Error     string     `json:"error"`
+	Season    *seasonDTO `json:"season"`
+}
+
+// Run Starts accepted seasons until ctx is cancelled. A start interrupted by cancellation fails, but can be retried
+// since stats are archived before and reset within a transaction.
+func (h *Handler) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case s := <-h.starts:
+			started, err := h.start(ctx, s)
+
+			h.mu.Lock()
+			h.status.Running = false
+			// Will overflow on 7 February 2106 at 06:28:15 UTC
+			h.status.Finished = uint32(time.Now().UTC().Unix())
+			if err != nil {
+				h.status.Error = err.Error()
+			} else {
+				h.status.Season = &seasonDTO{
+					ID:    started.ID,
+					Name:  started.Name,
+					Start: started.Start,
+				}
+			}
+			h.mu.Unlock()
+
+			if err != nil {
+				log.Ctx(ctx).Error().
+					Err(err).
+					Str("name", s.name).
+					Msg("Failed to start season")
+			}
+		}
+	}
+}
+
+// HandlePOST Accepts a start of a new season, which archives the running season (if any) and optionally resets all stats
+func (h *Handler) HandlePOST(c echo.Context) error {
+	params := struct {
+		Name  string `json:"name" form:"name" validate:"required,max=64"`
+		Reset bool   `json:"reset" form:"reset"`
+	}{}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.status != nil && h.status.Running {
+		return echo.NewHTTPError(http.StatusConflict).SetInternal(fmt.Errorf("season %q is still being started", h.status.Name))
+	}
+
+	h.status = &statusDTO{
+		Name:    params.Name,
+		Reset:   params.Reset,
+		Running: true,
+		// Will overflow on 7 February 2106 at 06:28:15 UTC
+		Requested: uint32(time.Now().UTC().Unix()),
+	}
+	// Cannot block, since the previous start (if any) has already been taken off the channel
+	h.starts <- start{
+		name:  params.Name,
+		reset: params.Reset,
+	}
+
+	return c.JSON(http.StatusAccepted, h.status)
+}
+
+// HandleGETStatus Returns the status of the most recent start
+func (h *Handler) HandleGETStatus(c echo.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.status == nil {
+		return echo.NewHTTPError(http.StatusNotFound).SetInternal(fmt.Errorf("no season has been started"))
+	}
+
+	return c.JSON(http.StatusOK, h.status)
+}
+
+func (h *Handler) start(ctx context.Context, s start) (season.Season, error) {
+	current, err := h.seasonRepository.FindCurrent(ctx)
+	if err != nil && !errors.Is(err, season.ErrSeasonNotFound) {
+		return season.Season{}, fmt.Errorf("failed to find current season: %w", err)
+	}
+
+	// There is nothing to archive when starting the first season
+	if err == nil {
+		// Archive before ending the season, so a failed archive can simply be retried
+		if err = h.seasonArchiver.Archive(ctx, current.ID); err != nil {
+			return season.Season{}, fmt.Errorf("failed to archive season: %w", err)
+		}
+
+		log.Ctx(ctx).Info().
+			Uint32("season", current.ID).
+			Msg("Archived season")
+	}
+
+	if s.reset {
+		if err = h.seasonArchiver.Reset(ctx); err != nil {
+			return season.Season{}, fmt.Errorf("failed to reset stats: %w", err)
+		}
+
+		log.Ctx(ctx).Info().
+			Msg("Reset stats")
+
+		// Stats are reset either way, so failing to invalidate caches should not keep the season from starting
+		if err = h.invalidator.InvalidateStats(ctx); err != nil {
+			log.Ctx(ctx).Error().
+				Err(err).
+				Msg("Failed to invalidate cached stats")
+		}
+	}
+
+	// Will overflow on 7 February 2106 at 06:28:15 UTC
+	started, err := h.seasonRepository.Start(ctx, s.name, uint32(time.Now().UTC().Unix()))
+	if err != nil {
+		return season.Season{}, fmt.Errorf("failed to start season: %w", err)
+	}
+
+	log.Ctx(ctx).Info().
+		Uint32("season", started.ID).
+		Str("name", started.Name).
+		Msg("Started season")
+
+	return started, nil
+}
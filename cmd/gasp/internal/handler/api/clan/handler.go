@@ -0,0 +1,216 @@
+package clan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/domain/clan"
+	"github.com/cetteup/gasp/pkg/task"
+)
+
+var (
+	rankBys = map[string]clan.RankBy{
+		"score": clan.RankByScore,
+		"kills": clan.RankByKills,
+		"time":  clan.RankByTime,
+		"rank":  clan.RankByAverageRank,
+	}
+)
+
+type Handler struct {
+	clanRepository      clan.Repository
+	clanStatsRepository clan.StatsRepository
+}
+
+func NewHandler(clanRepository clan.Repository, clanStatsRepository clan.StatsRepository) *Handler {
+	return &Handler{
+		clanRepository:      clanRepository,
+		clanStatsRepository: clanStatsRepository,
+	}
+}
+
+type leaderboardDTO struct {
+	Total   int        `json:"total"`
+	Entries []entryDTO `json:"entries"`
+}
+
+type entryDTO struct {
+	Position uint32   `json:"position"`
+	Clan     refDTO   `json:"clan"`
+	Stats    statsDTO `json:"stats"`
+}
+
+type clanDTO struct {
+	ID      uint32      `json:"id"`
+	Tag     string      `json:"tag"`
+	Name    string      `json:"name"`
+	Created uint32      `json:"created"`
+	Members []memberDTO `json:"members"`
+	Stats   statsDTO    `json:"stats"`
+}
+
+type refDTO struct {
+	ID   uint32 `json:"id"`
+	Tag  string `json:"tag"`
+	Name string `json:"name"`
+}
+
+type memberDTO struct {
+	PID    uint32 `json:"pid"`
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	Joined uint32 `json:"joined"`
+}
+
+type statsDTO struct {
+	Members     int     `json:"members"`
+	Score       int64   `json:"score"`
+	Kills       uint64  `json:"kills"`
+	Deaths      uint64  `json:"deaths"`
+	Time        uint64  `json:"time"`
+	AverageRank float64 `json:"averageRank"`
+}
+
+// HandleGETLeaderboard Lists clans ranked by their members' combined stats
+func (h *Handler) HandleGETLeaderboard(c echo.Context) error {
+	params := struct {
+		By     string `query:"by" validate:"oneof=score kills time rank"`
+		Offset uint32 `query:"offset"`
+		Limit  uint32 `query:"limit" validate:"min=1,max=100"`
+	}{
+		// Default values
+		By:     "score",
+		Offset: 0,
+		Limit:  20,
+	}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	stats, total, err := h.clanStatsRepository.FindTop(c.Request().Context(), rankBys[params.By], params.Offset, params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find clan stats: %w", err))
+	}
+
+	entries := make([]entryDTO, 0, len(stats))
+	for i, s := range stats {
+		entries = append(entries, entryDTO{
+			Position: params.Offset + uint32(i) + 1,
+			Clan: refDTO{
+				ID:   s.Clan.ID,
+				Tag:  s.Clan.Tag,
+				Name: s.Clan.Name,
+			},
+			Stats: toStatsDTO(s),
+		})
+	}
+
+	return c.JSON(http.StatusOK, leaderboardDTO{
+		Total:   total,
+		Entries: entries,
+	})
+}
+
+// HandleGET Returns the clan along with its members and their combined stats
+func (h *Handler) HandleGET(c echo.Context) error {
+	params := struct {
+		ID uint32 `param:"id" validate:"required"`
+	}{}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	var cl clan.Clan
+	var stats clan.Stats
+	var runner task.AsyncRunner
+	runner.Append(func(ctx context.Context) error {
+		var err error
+		cl, err = h.clanRepository.FindByID(ctx, params.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find clan: %w", err)
+		}
+		return nil
+	})
+	runner.Append(func(ctx context.Context) error {
+		var err error
+		stats, err = h.clanStatsRepository.FindByClanID(ctx, params.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find clan stats: %w", err)
+		}
+		return nil
+	})
+
+	if err := runner.Run(c.Request().Context()); err != nil {
+		if errors.Is(err, clan.ErrClanNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, toClanDTO(cl, stats))
+}
+
+// HandleGETByPlayer Returns the clan the player is a member of
+func (h *Handler) HandleGETByPlayer(c echo.Context) error {
+	params := struct {
+		PID uint32 `param:"pid" validate:"required"`
+	}{}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	cl, err := h.clanRepository.FindByPlayerID(c.Request().Context(), params.PID)
+	if err != nil {
+		if errors.Is(err, clan.ErrClanNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find clan: %w", err))
+	}
+
+	stats, err := h.clanStatsRepository.FindByClanID(c.Request().Context(), cl.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find clan stats: %w", err))
+	}
+
+	return c.JSON(http.StatusOK, toClanDTO(cl, stats))
+}
+
+func toClanDTO(cl clan.Clan, stats clan.Stats) clanDTO {
+	members := make([]memberDTO, 0, len(cl.Members))
+	for _, m := range cl.Members {
+		members = append(members, memberDTO{
+			PID:    m.Player.ID,
+			Name:   m.Player.Name,
+			Role:   m.Role.String(),
+			Joined: m.Joined,
+		})
+	}
+
+	return clanDTO{
+		ID:      cl.ID,
+		Tag:     cl.Tag,
+		Name:    cl.Name,
+		Created: cl.Created,
+		Members: members,
+		Stats:   toStatsDTO(stats),
+	}
+}
+
+func toStatsDTO(s clan.Stats) statsDTO {
+	return statsDTO{
+		Members:     s.Members,
+		Score:       s.Score,
+		Kills:       s.Kills,
+		Deaths:      s.Deaths,
+		Time:        s.Time,
+		AverageRank: s.AverageRank,
+	}
+}
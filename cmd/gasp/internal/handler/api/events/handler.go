@@ -0,0 +1,126 @@
+package events
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/bus"
+	"github.com/cetteup/gasp/internal/domain/event"
+	"github.com/cetteup/gasp/internal/eventjson"
+)
+
+const (
+	// keepAliveInterval Proxies tend to close connections which are idle for a minute or longer
+	keepAliveInterval = time.Second * 15
+)
+
+type Handler struct {
+	bus    *bus.Bus
+	buffer int
+}
+
+func NewHandler(bus *bus.Bus, buffer int) *Handler {
+	return &Handler{
+		bus:    bus,
+		buffer: buffer,
+	}
+}
+
+// HandleGET Streams events as server-sent events until the client disconnects. Events can be filtered by player
+// (events involving the player), server (rounds ingested from the server) and type (comma separated).
+func (h *Handler) HandleGET(c echo.Context) error {
+	params := struct {
+		PID    uint32 `query:"pid"`
+		Server uint32 `query:"server"`
+		Types  string `query:"types"`
+	}{}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	var types []event.Type
+	if params.Types != "" {
+		for _, t := range strings.Split(params.Types, ",") {
+			if !slices.Contains(event.Types, event.Type(t)) {
+				return request.NewInvalidParametersError(fmt.Errorf("unknown event type: %s", t))
+			}
+			types = append(types, event.Type(t))
+		}
+	}
+
+	sub := h.bus.Subscribe(h.buffer, func(e event.Event) bool {
+		if len(types) > 0 && !slices.Contains(types, e.Type) {
+			return false
+		}
+		if params.PID != 0 && !e.Involves(params.PID) {
+			return false
+		}
+		// Only rounds are related to a server
+		if params.Server != 0 && (e.Round == nil || e.Round.ServerID != params.Server) {
+			return false
+		}
+		return true
+	})
+	defer sub.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	// Disable response buffering in nginx
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	var dropped uint64
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-ticker.C:
+			// Comments are ignored by clients, but keep the connection from being considered idle
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		case e, ok := <-sub.Events():
+			// Bus was closed (shutdown)
+			if !ok {
+				return nil
+			}
+
+			data, err := eventjson.Marshal(e)
+			if err != nil {
+				log.Ctx(c.Request().Context()).Error().
+					Err(err).
+					Str("type", string(e.Type)).
+					Msg("Failed to encode event")
+				continue
+			}
+
+			if _, err = fmt.Fprintf(res, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+				return nil
+			}
+		}
+
+		// Let the client know it missed events (e.g. to refresh its state), since the stream cannot be resumed
+		if d := sub.Dropped(); d != dropped {
+			if _, err := fmt.Fprintf(res, "event: dropped\ndata: {\"count\":%d}\n\n", d-dropped); err != nil {
+				return nil
+			}
+			dropped = d
+		}
+
+		res.Flush()
+	}
+}
@@ -0,0 +1,115 @@
+package killhistory
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/domain/kill"
+)
+
+type Handler struct {
+	killHistoryRecordRepository kill.HistoryRecordRepository
+}
+
+func NewHandler(killHistoryRecordRepository kill.HistoryRecordRepository) *Handler {
+	return &Handler{
+		killHistoryRecordRepository: killHistoryRecordRepository,
+	}
+}
+
+type relatedDTO struct {
+	Total   int         `json:"total"`
+	Players []playerDTO `json:"players"`
+}
+
+type playerDTO struct {
+	ID    uint32 `json:"id"`
+	Name  string `json:"name"`
+	Rank  uint32 `json:"rank"`
+	Kills uint16 `json:"kills"`
+}
+
+type headToHeadDTO struct {
+	PID    uint32 `json:"pid"`
+	Other  uint32 `json:"other"`
+	Kills  uint16 `json:"kills"`
+	Deaths uint16 `json:"deaths"`
+}
+
+// HandleGETVictims Lists the players killed by the player, along with how often they were killed
+func (h *Handler) HandleGETVictims(c echo.Context) error {
+	return h.handleGETRelated(c, kill.RelationTypeVictim)
+}
+
+// HandleGETAttackers Lists the players who killed the player, along with how often they killed the player
+func (h *Handler) HandleGETAttackers(c echo.Context) error {
+	return h.handleGETRelated(c, kill.RelationTypeAttacker)
+}
+
+func (h *Handler) HandleGETHeadToHead(c echo.Context) error {
+	params := struct {
+		PID   uint32 `param:"pid" validate:"required"`
+		Other uint32 `param:"other" validate:"required,nefield=PID"`
+	}{}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	h2h, err := h.killHistoryRecordRepository.FindHeadToHead(c.Request().Context(), params.PID, params.Other)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find head to head: %w", err))
+	}
+
+	return c.JSON(http.StatusOK, headToHeadDTO{
+		PID:    h2h.Player.ID,
+		Other:  h2h.Other.ID,
+		Kills:  h2h.Kills,
+		Deaths: h2h.Deaths,
+	})
+}
+
+func (h *Handler) handleGETRelated(c echo.Context, relationType kill.RelationType) error {
+	params := struct {
+		PID    uint32 `param:"pid" validate:"required"`
+		Offset uint32 `query:"offset"`
+		Limit  uint32 `query:"limit" validate:"min=1,max=100"`
+	}{
+		// Default values
+		Offset: 0,
+		Limit:  20,
+	}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	records, total, err := h.killHistoryRecordRepository.FindRelatedByPlayerID(
+		c.Request().Context(),
+		params.PID,
+		relationType,
+		params.Offset,
+		params.Limit,
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find kill history records: %w", err))
+	}
+
+	players := make([]playerDTO, 0, len(records))
+	for _, record := range records {
+		players = append(players, playerDTO{
+			ID:    record.Other.ID,
+			Name:  record.Other.Name,
+			Rank:  record.Other.RankID,
+			Kills: record.Kills,
+		})
+	}
+
+	return c.JSON(http.StatusOK, relatedDTO{
+		Total:   total,
+		Players: players,
+	})
+}
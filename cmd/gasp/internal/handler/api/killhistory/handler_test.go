@@ -0,0 +1,188 @@
+package killhistory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/catalogue"
+	"github.com/cetteup/gasp/internal/domain/kill"
+)
+
+type relatedCall struct {
+	playerID     uint32
+	relationType kill.RelationType
+	offset       uint32
+	limit        uint32
+}
+
+// historyRecordRepository Records calls, returning a single related player for any player
+type historyRecordRepository struct {
+	kill.HistoryRecordRepository
+	calls []relatedCall
+}
+
+func (r *historyRecordRepository) FindRelatedByPlayerID(_ context.Context, playerID uint32, relationType kill.RelationType, offset, limit uint32) ([]kill.HistoryRecord, int, error) {
+	r.calls = append(r.calls, relatedCall{playerID: playerID, relationType: relationType, offset: offset, limit: limit})
+	return []kill.HistoryRecord{
+		{
+			Player:       kill.PlayerRef{ID: playerID},
+			Other:        kill.PlayerStub{ID: 7, Name: "other", RankID: 12},
+			Kills:        3,
+			RelationType: relationType,
+		},
+	}, 21, nil
+}
+
+func (r *historyRecordRepository) FindHeadToHead(_ context.Context, playerID, otherID uint32) (kill.HeadToHead, error) {
+	return kill.HeadToHead{
+		Player: kill.PlayerRef{ID: playerID},
+		Other:  kill.PlayerRef{ID: otherID},
+		Kills:  4,
+		Deaths: 2,
+	}, nil
+}
+
+func newTestServer(t *testing.T, repository kill.HistoryRecordRepository) *echo.Echo {
+	t.Helper()
+
+	cat, err := catalogue.Load("")
+	if err != nil {
+		t.Fatalf("failed to load catalogue: %v", err)
+	}
+	validator, err := request.NewValidator(cat)
+	if err != nil {
+		t.Fatalf("failed to set up validator: %v", err)
+	}
+
+	h := NewHandler(repository)
+	e := echo.New()
+	e.Validator = validator
+	e.GET("/players/:pid/victims", h.HandleGETVictims)
+	e.GET("/players/:pid/attackers", h.HandleGETAttackers)
+	e.GET("/players/:pid/versus/:other", h.HandleGETHeadToHead)
+	return e
+}
+
+func TestHandler_HandleGETRelated(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantCall   relatedCall
+	}{
+		{
+			name:       "victims with default page",
+			target:     "/players/42/victims",
+			wantStatus: http.StatusOK,
+			wantCall:   relatedCall{playerID: 42, relationType: kill.RelationTypeVictim, offset: 0, limit: 20},
+		},
+		{
+			name:       "attackers with page",
+			target:     "/players/42/attackers?offset=20&limit=100",
+			wantStatus: http.StatusOK,
+			wantCall:   relatedCall{playerID: 42, relationType: kill.RelationTypeAttacker, offset: 20, limit: 100},
+		},
+		{
+			name:       "limit too small",
+			target:     "/players/42/victims?limit=0",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "limit too large",
+			target:     "/players/42/victims?limit=101",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid player id",
+			target:     "/players/abc/victims",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &historyRecordRepository{}
+			e := newTestServer(t, repository)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if len(repository.calls) != 0 {
+					t.Errorf("expected no repository calls, got %v", repository.calls)
+				}
+				return
+			}
+
+			if len(repository.calls) != 1 || repository.calls[0] != tt.wantCall {
+				t.Errorf("expected call %+v, got %+v", tt.wantCall, repository.calls)
+			}
+
+			var got relatedDTO
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			want := relatedDTO{Total: 21, Players: []playerDTO{{ID: 7, Name: "other", Rank: 12, Kills: 3}}}
+			if got.Total != want.Total || len(got.Players) != 1 || got.Players[0] != want.Players[0] {
+				t.Errorf("expected %+v, got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestHandler_HandleGETHeadToHead(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		want       headToHeadDTO
+	}{
+		{
+			name:       "two players",
+			target:     "/players/42/versus/7",
+			wantStatus: http.StatusOK,
+			want:       headToHeadDTO{PID: 42, Other: 7, Kills: 4, Deaths: 2},
+		},
+		{
+			name:       "same player",
+			target:     "/players/42/versus/42",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "zero other player",
+			target:     "/players/42/versus/0",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestServer(t, &historyRecordRepository{})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got headToHeadDTO
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/catalogue"
+	"github.com/cetteup/gasp/internal/domain/leaderboard"
+	"github.com/cetteup/gasp/internal/domain/season"
+)
+
+const (
+	typeScore      = "score"
+	typeKit        = "kit"
+	typeVehicle    = "vehicle"
+	typeWeapon     = "weapon"
+	typeRisingStar = "risingstar"
+	typeArmy       = "army"
+	typeField      = "map"
+)
+
+var (
+	errInvalidLeaderboardType   = errors.New("invalid leaderboard type")
+	errInvalidLeaderboardID     = errors.New("invalid leaderboard id")
+	errInvalidLeaderboardBy     = errors.New("invalid leaderboard by")
+	errInvalidLeaderboardWindow = errors.New("invalid leaderboard window")
+
+	scoreTypes = map[string]leaderboard.ScoreType{
+		"overall":   leaderboard.ScoreTypeOverall,
+		"combat":    leaderboard.ScoreTypeCombat,
+		"commander": leaderboard.ScoreTypeCommand,
+		"team":      leaderboard.ScoreTypeTeam,
+	}
+	armyRankBys = map[string]leaderboard.ArmyRankBy{
+		"":      leaderboard.ArmyRankByScore,
+		"score": leaderboard.ArmyRankByScore,
+		"wins":  leaderboard.ArmyRankByWins,
+	}
+	fieldRankBys = map[string]leaderboard.FieldRankBy{
+		"":     leaderboard.FieldRankByWins,
+		"wins": leaderboard.FieldRankByWins,
+		"time": leaderboard.FieldRankByTime,
+	}
+	windows = map[string]leaderboard.Window{
+		"day":   leaderboard.WindowDay,
+		"week":  leaderboard.WindowWeek,
+		"month": leaderboard.WindowMonth,
+	}
+)
+
+type Handler struct {
+	leaderboardRepository leaderboard.Repository
+	seasonRepository      season.Repository
+	catalogue             *catalogue.Catalogue
+}
+
+func NewHandler(
+	leaderboardRepository leaderboard.Repository,
+	seasonRepository season.Repository,
+	catalogue *catalogue.Catalogue,
+) *Handler {
+	return &Handler{
+		leaderboardRepository: leaderboardRepository,
+		seasonRepository:      seasonRepository,
+		catalogue:             catalogue,
+	}
+}
+
+type responseDTO struct {
+	Size    int        `json:"size"`
+	AsOf    uint32     `json:"asOf"`
+	Entries []entryDTO `json:"entries"`
+}
+
+type entryDTO struct {
+	Position uint32    `json:"position"`
+	Player   playerDTO `json:"player"`
+	// Record Type specific stats, omitted for score leaderboards (all of which are part of the player)
+	Record any `json:"record,omitempty"`
+}
+
+type playerDTO struct {
+	ID           uint32 `json:"id"`
+	Name         string `json:"name"`
+	Country      string `json:"country"`
+	Joined       uint32 `json:"joined"`
+	Rank         uint8  `json:"rank"`
+	Time         uint32 `json:"time"`
+	Score        int64  `json:"score"`
+	CommandScore int64  `json:"commandScore"`
+	CombatScore  int64  `json:"combatScore"`
+	TeamScore    int64  `json:"teamScore"`
+	Kills        uint64 `json:"kills"`
+	CommandTime  uint32 `json:"commandTime"`
+}
+
+type kitRecordDTO struct {
+	Time   uint32 `json:"time"`
+	Score  int    `json:"score"`
+	Kills  uint32 `json:"kills"`
+	Deaths uint32 `json:"deaths"`
+}
+
+type vehicleRecordDTO struct {
+	Time      uint32 `json:"time"`
+	Score     int    `json:"score"`
+	Kills     uint32 `json:"kills"`
+	Deaths    uint32 `json:"deaths"`
+	RoadKills uint32 `json:"roadKills"`
+}
+
+type weaponRecordDTO struct {
+	Time          uint32 `json:"time"`
+	Score         int    `json:"score"`
+	Kills         uint32 `json:"kills"`
+	Deaths        uint32 `json:"deaths"`
+	ShotsFired    uint32 `json:"shotsFired"`
+	ShotsHit      uint32 `json:"shotsHit"`
+	TimesDeployed uint16 `json:"timesDeployed"`
+}
+
+type armyRecordDTO struct {
+	Time   uint32 `json:"time"`
+	Wins   uint16 `json:"wins"`
+	Losses uint16 `json:"losses"`
+	Score  int    `json:"score"`
+}
+
+type fieldRecordDTO struct {
+	Time   uint32 `json:"time"`
+	Wins   uint16 `json:"wins"`
+	Losses uint16 `json:"losses"`
+}
+
+type risingStarDTO struct {
+	WeeklyScore float64 `json:"weeklyScore"`
+}
+
+func (h *Handler) HandleGET(c echo.Context) error {
+	params := struct {
+		Type    string  `param:"type" validate:"required,oneof=score kit vehicle weapon risingstar army map"`
+		ID      string  `query:"id" validate:"required_unless=Type risingstar"`
+		By      string  `query:"by" validate:"omitempty,oneof=score wins time"`
+		Offset  uint32  `query:"offset"`
+		Limit   uint32  `query:"limit" validate:"min=1,max=100"`
+		PID     *uint32 `query:"pid"`
+		Country string  `query:"country" validate:"omitempty,len=2,alpha"`
+		Window  string  `query:"window" validate:"omitempty,oneof=day week month season"`
+	}{
+		// Default values
+		Offset: 0,
+		Limit:  20,
+	}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	var filter leaderboard.Filter
+	if params.PID != nil {
+		filter = leaderboard.NewPIDFilter(*params.PID)
+	} else {
+		filter = leaderboard.NewPositionFilter(params.Offset, params.Offset+params.Limit)
+	}
+	filter.Country = params.Country
+
+	if params.Window != "" {
+		// Only rounds' score/kit/vehicle/weapon stats are recorded
+		if params.Type != typeScore && params.Type != typeKit && params.Type != typeVehicle && params.Type != typeWeapon {
+			return request.NewInvalidParametersError(errInvalidLeaderboardWindow)
+		}
+		since, err := h.getWindowStart(c.Request().Context(), params.Window)
+		if err != nil {
+			if errors.Is(err, season.ErrSeasonNotFound) {
+				return request.NewInvalidParametersError(errInvalidLeaderboardWindow)
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find season: %w", err))
+		}
+		filter.Since = since
+	}
+
+	resp, err := h.find(c.Request().Context(), params.Type, params.ID, params.By, filter)
+	if err != nil {
+		if errors.Is(err, errInvalidLeaderboardType) ||
+			errors.Is(err, errInvalidLeaderboardID) ||
+			errors.Is(err, errInvalidLeaderboardBy) {
+			return request.NewInvalidParametersError(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find leaderboard: %w", err))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) find(ctx context.Context, t, id, by string, filter leaderboard.Filter) (responseDTO, error) {
+	var resp responseDTO
+	var err error
+	switch t {
+	case typeScore:
+		scoreType, ok := scoreTypes[id]
+		if !ok {
+			return responseDTO{}, errInvalidLeaderboardID
+		}
+		resp, err = find(ctx, filter, func(ctx context.Context, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.PlayerStub], int, error) {
+			return h.leaderboardRepository.FindTopPlayersByScore(ctx, scoreType, filter)
+		}, func(data leaderboard.PlayerStub) entryDTO {
+			return entryDTO{Player: toPlayerDTO(data)}
+		})
+	case typeKit:
+		kitID, err2 := toID(id, h.catalogue.KitIDs())
+		if err2 != nil {
+			return responseDTO{}, err2
+		}
+		resp, err = find(ctx, filter, func(ctx context.Context, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.KitRecord], int, error) {
+			return h.leaderboardRepository.FindTopPlayersByKit(ctx, kitID, filter)
+		}, func(data leaderboard.KitRecord) entryDTO {
+			return entryDTO{
+				Player: toPlayerDTO(data.Player),
+				Record: kitRecordDTO{
+					Time:   data.Time,
+					Score:  data.Score,
+					Kills:  data.Kills,
+					Deaths: data.Deaths,
+				},
+			}
+		})
+	case typeVehicle:
+		vehicleID, err2 := toID(id, h.catalogue.VehicleIDs())
+		if err2 != nil {
+			return responseDTO{}, err2
+		}
+		resp, err = find(ctx, filter, func(ctx context.Context, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.VehicleRecord], int, error) {
+			return h.leaderboardRepository.FindTopPlayersByVehicle(ctx, vehicleID, filter)
+		}, func(data leaderboard.VehicleRecord) entryDTO {
+			return entryDTO{
+				Player: toPlayerDTO(data.Player),
+				Record: vehicleRecordDTO{
+					Time:      data.Time,
+					Score:     data.Score,
+					Kills:     data.Kills,
+					Deaths:    data.Deaths,
+					RoadKills: data.RoadKills,
+				},
+			}
+		})
+	case typeWeapon:
+		weaponID, err2 := toID(id, h.catalogue.WeaponIDs())
+		if err2 != nil {
+			return responseDTO{}, err2
+		}
+		resp, err = find(ctx, filter, func(ctx context.Context, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.WeaponRecord], int, error) {
+			return h.leaderboardRepository.FindTopPlayersByWeapon(ctx, weaponID, filter)
+		}, func(data leaderboard.WeaponRecord) entryDTO {
+			return entryDTO{
+				Player: toPlayerDTO(data.Player),
+				Record: weaponRecordDTO{
+					Time:          data.Time,
+					Score:         data.Score,
+					Kills:         data.Kills,
+					Deaths:        data.Deaths,
+					ShotsFired:    data.ShotsFired,
+					ShotsHit:      data.ShotsHit,
+					TimesDeployed: data.TimesDeployed,
+				},
+			}
+		})
+	case typeArmy:
+		armyID, err2 := toID(id, h.catalogue.ArmyIDs())
+		if err2 != nil {
+			return responseDTO{}, err2
+		}
+		rankBy, ok := armyRankBys[by]
+		if !ok {
+			return responseDTO{}, errInvalidLeaderboardBy
+		}
+		resp, err = find(ctx, filter, func(ctx context.Context, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.ArmyRecord], int, error) {
+			return h.leaderboardRepository.FindTopPlayersByArmy(ctx, armyID, rankBy, filter)
+		}, func(data leaderboard.ArmyRecord) entryDTO {
+			return entryDTO{
+				Player: toPlayerDTO(data.Player),
+				Record: armyRecordDTO{
+					Time:   data.Time,
+					Wins:   data.Wins,
+					Losses: data.Losses,
+					Score:  data.Score,
+				},
+			}
+		})
+	case typeField:
+		fieldID, err2 := toID(id, h.catalogue.FieldIDs())
+		if err2 != nil {
+			return responseDTO{}, err2
+		}
+		rankBy, ok := fieldRankBys[by]
+		if !ok {
+			return responseDTO{}, errInvalidLeaderboardBy
+		}
+		resp, err = find(ctx, filter, func(ctx context.Context, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.FieldRecord], int, error) {
+			return h.leaderboardRepository.FindTopPlayersByField(ctx, fieldID, rankBy, filter)
+		}, func(data leaderboard.FieldRecord) entryDTO {
+			return entryDTO{
+				Player: toPlayerDTO(data.Player),
+				Record: fieldRecordDTO{
+					Time:   data.Time,
+					Wins:   data.Wins,
+					Losses: data.Losses,
+				},
+			}
+		})
+	case typeRisingStar:
+		resp, err = find(ctx, filter, h.leaderboardRepository.FindRisingStars, func(data leaderboard.RisingStar) entryDTO {
+			return entryDTO{
+				Player: toPlayerDTO(data.Player),
+				Record: risingStarDTO{
+					WeeklyScore: float64(data.WeeklyScore) / 10000,
+				},
+			}
+		})
+		if err != nil {
+			return responseDTO{}, err
+		}
+		// Rising stars are updated separately from all other leaderboards
+		resp.AsOf, err = h.leaderboardRepository.GetRisingStarUpdateTimestamp(ctx)
+		return resp, err
+	default:
+		return responseDTO{}, errInvalidLeaderboardType
+	}
+	if err != nil {
+		return responseDTO{}, err
+	}
+
+	// Leaderboards may be served from a snapshot, so they are not necessarily as of now
+	resp.AsOf, err = h.leaderboardRepository.GetUpdateTimestamp(ctx, filter)
+	return resp, err
+}
+
+func (h *Handler) getWindowStart(ctx context.Context, window string) (uint32, error) {
+	if w, ok := windows[window]; ok {
+		return w.Since(time.Now()), nil
+	}
+
+	// Use the season the request is scoped to, falling back to the running season
+	var s season.Season
+	var err error
+	if id, ok := season.FromContext(ctx); ok {
+		s, err = h.seasonRepository.FindByID(ctx, id)
+	} else {
+		s, err = h.seasonRepository.FindCurrent(ctx)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return s.Start, nil
+}
+
+func find[T any](
+	ctx context.Context,
+	filter leaderboard.Filter,
+	load func(ctx context.Context, filter leaderboard.Filter) ([]leaderboard.Entry[T], int, error),
+	convert func(data T) entryDTO,
+) (responseDTO, error) {
+	entries, size, err := load(ctx, filter)
+	if err != nil {
+		return responseDTO{}, err
+	}
+
+	dtos := make([]entryDTO, 0, len(entries))
+	for _, entry := range entries {
+		dto := convert(entry.Data)
+		dto.Position = entry.Position
+		dtos = append(dtos, dto)
+	}
+
+	return responseDTO{
+		Size:    size,
+		Entries: dtos,
+	}, nil
+}
+
+func toPlayerDTO(p leaderboard.PlayerStub) playerDTO {
+	return playerDTO{
+		ID:           p.ID,
+		Name:         p.Name,
+		Country:      strings.ToUpper(p.Country),
+		Joined:       p.Joined,
+		Rank:         p.Rank.ID,
+		Time:         p.Time,
+		Score:        p.Score,
+		CommandScore: p.CommandScore,
+		CombatScore:  p.CombatScore,
+		TeamScore:    p.TeamScore,
+		Kills:        p.Kills,
+		CommandTime:  p.CommandTime,
+	}
+}
+
+// toID Unlike the ASP, the JSON API uses domain ids
+func toID[T uint8 | uint16](id string, valid []T) (T, error) {
+	i, err := strconv.ParseUint(id, 10, 16)
+	if err != nil {
+		return 0, errInvalidLeaderboardID
+	}
+
+	// Casting may truncate the id, so make sure it fits before checking whether it's valid
+	if uint64(T(i)) != i || !slices.Contains(valid, T(i)) {
+		return 0, errInvalidLeaderboardID
+	}
+
+	return T(i), nil
+}
@@ -0,0 +1,150 @@
+package player
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/domain/player"
+)
+
+const (
+	matchContains   = "contains"
+	matchBeginsWith = "begins"
+	matchEndsWith   = "ends"
+	matchEquals     = "equals"
+
+	sortName       = "name"
+	sortScore      = "score"
+	sortRank       = "rank"
+	sortLastOnline = "lastOnline"
+
+	orderASC  = "asc"
+	orderDESC = "desc"
+)
+
+type Handler struct {
+	playerRepository player.Repository
+}
+
+func NewHandler(playerRepository player.Repository) *Handler {
+	return &Handler{
+		playerRepository: playerRepository,
+	}
+}
+
+type searchDTO struct {
+	Total   int         `json:"total"`
+	Players []playerDTO `json:"players"`
+}
+
+type playerDTO struct {
+	ID         uint32 `json:"id"`
+	Name       string `json:"name"`
+	Rank       uint8  `json:"rank"`
+	Score      int64  `json:"score"`
+	LastOnline uint32 `json:"lastOnline"`
+}
+
+// HandleGETSearch Searches players by name, ignoring case and clan tags. Unlike the ASP's search, results are paginated
+// and can be sorted by score, rank or last online. Fuzzy searches return names within the given edit distance.
+func (h *Handler) HandleGETSearch(c echo.Context) error {
+	params := struct {
+		Query    string `query:"q" validate:"required,max=32"`
+		Match    string `query:"match" validate:"oneof=contains begins ends equals"`
+		Fuzzy    bool   `query:"fuzzy"`
+		Distance int    `query:"distance" validate:"min=1,max=3"`
+		Sort     string `query:"sort" validate:"oneof=name score rank lastOnline"`
+		Order    string `query:"order" validate:"omitempty,oneof=asc desc"`
+		Offset   uint32 `query:"offset"`
+		Limit    uint32 `query:"limit" validate:"min=1,max=100"`
+	}{
+		// Default values
+		Match:    matchContains,
+		Distance: 2,
+		Sort:     sortName,
+		Offset:   0,
+		Limit:    20,
+	}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	players, total, err := h.playerRepository.Search(c.Request().Context(), player.SearchOptions{
+		Name:        params.Query,
+		Condition:   toMatchCondition(params.Match),
+		Fuzzy:       params.Fuzzy,
+		MaxDistance: params.Distance,
+		Sort:        toSearchSort(params.Sort),
+		Order:       toSortOrder(params.Order, params.Sort),
+		Offset:      params.Offset,
+		Limit:       params.Limit,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to search players: %w", err))
+	}
+
+	dtos := make([]playerDTO, 0, len(players))
+	for _, p := range players {
+		dtos = append(dtos, playerDTO{
+			ID:         p.ID,
+			Name:       p.Name,
+			Rank:       p.Rank.ID,
+			Score:      p.Score,
+			LastOnline: p.LastOnline,
+		})
+	}
+
+	return c.JSON(http.StatusOK, searchDTO{
+		Total:   total,
+		Players: dtos,
+	})
+}
+
+func toMatchCondition(match string) player.MatchCondition {
+	switch match {
+	case matchBeginsWith:
+		return player.MatchConditionBeginsWith
+	case matchEndsWith:
+		return player.MatchConditionEndsWith
+	case matchEquals:
+		return player.MatchConditionEquals
+	case matchContains:
+		fallthrough
+	default:
+		return player.MatchConditionContains
+	}
+}
+
+func toSearchSort(sort string) player.SearchSort {
+	switch sort {
+	case sortScore:
+		return player.SearchSortScore
+	case sortRank:
+		return player.SearchSortRank
+	case sortLastOnline:
+		return player.SearchSortLastOnline
+	case sortName:
+		fallthrough
+	default:
+		return player.SearchSortName
+	}
+}
+
+// toSortOrder Defaults to alphabetical order for names and to highest/most recent first for anything else
+func toSortOrder(order string, sort string) player.SortOrder {
+	switch order {
+	case orderASC:
+		return player.SortOrderASC
+	case orderDESC:
+		return player.SortOrderDESC
+	default:
+		if sort == sortName {
+			return player.SortOrderASC
+		}
+		return player.SortOrderDESC
+	}
+}
@@ -0,0 +1,359 @@
+package round
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/middleware/serverauth"
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/domain/event"
+	"github.com/cetteup/gasp/internal/domain/round"
+)
+
+type Handler struct {
+	roundHistoryRepository round.HistoryRepository
+	publisher              event.Publisher
+}
+
+func NewHandler(roundHistoryRepository round.HistoryRepository, publisher event.Publisher) *Handler {
+	return &Handler{
+		roundHistoryRepository: roundHistoryRepository,
+		publisher:              publisher,
+	}
+}
+
+type roundDTO struct {
+	ID       uint32    `json:"id"`
+	Start    uint32    `json:"start"`
+	End      uint32    `json:"end"`
+	Duration uint32    `json:"duration"`
+	Map      uint16    `json:"map"`
+	Server   serverDTO `json:"server"`
+	Teams    []teamDTO `json:"teams"`
+}
+
+type serverDTO struct {
+	ID   uint32 `json:"id"`
+	Name string `json:"name"`
+}
+
+type teamDTO struct {
+	ID      uint8  `json:"id"`
+	Army    uint8  `json:"army"`
+	Tickets uint16 `json:"tickets"`
+	Winner  bool   `json:"winner"`
+}
+
+type historyDTO struct {
+	roundDTO
+	Players []playerRecordDTO `json:"players"`
+}
+
+type playerHistoryDTO struct {
+	Round  roundDTO        `json:"round"`
+	Record playerRecordDTO `json:"record"`
+}
+
+type playerRecordDTO struct {
+	PID          uint32         `json:"pid"`
+	Name         string         `json:"name"`
+	Team         uint8          `json:"team"`
+	Score        int            `json:"score"`
+	CommandScore int            `json:"commandScore"`
+	CombatScore  int            `json:"combatScore"`
+	TeamScore    int            `json:"teamScore"`
+	Kills        uint32         `json:"kills"`
+	Deaths       uint32         `json:"deaths"`
+	Time         uint32         `json:"time"`
+	CommandTime  uint32         `json:"commandTime"`
+	Kits         []kitRecordDTO `json:"kits"`
+}
+
+type kitRecordDTO struct {
+	ID     uint8  `json:"id"`
+	Time   uint32 `json:"time"`
+	Score  int    `json:"score"`
+	Kills  uint32 `json:"kills"`
+	Deaths uint32 `json:"deaths"`
+}
+
+type createdDTO struct {
+	ID uint32 `json:"id"`
+}
+
+// HandleGET Returns a round's details, including every participating player's record
+func (h *Handler) HandleGET(c echo.Context) error {
+	params := struct {
+		ID uint32 `param:"id" validate:"required"`
+	}{}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	history, err := h.roundHistoryRepository.FindByID(c.Request().Context(), params.ID)
+	if err != nil {
+		if errors.Is(err, round.ErrRoundNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound).SetInternal(fmt.Errorf("round not found: %d", params.ID))
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find round: %w", err))
+	}
+
+	players := make([]playerRecordDTO, 0, len(history.Players))
+	for _, record := range history.Players {
+		players = append(players, toPlayerRecordDTO(record))
+	}
+
+	return c.JSON(http.StatusOK, historyDTO{
+		roundDTO: toRoundDTO(history.Summary),
+		Players:  players,
+	})
+}
+
+// HandleGETByPlayer Returns the player's last rounds, most recent first
+func (h *Handler) HandleGETByPlayer(c echo.Context) error {
+	params := struct {
+		PID   uint32 `param:"pid" validate:"required"`
+		Limit uint32 `query:"limit" validate:"min=1,max=100"`
+	}{
+		// Default values
+		Limit: 10,
+	}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	histories, err := h.roundHistoryRepository.FindByPlayerID(c.Request().Context(), params.PID, params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find rounds: %w", err))
+	}
+
+	rounds := make([]playerHistoryDTO, 0, len(histories))
+	for _, history := range histories {
+		rounds = append(rounds, playerHistoryDTO{
+			Round:  toRoundDTO(history.Round),
+			Record: toPlayerRecordDTO(history.Record),
+		})
+	}
+
+	return c.JSON(http.StatusOK, rounds)
+}
+
+// HandlePOST Records a round played on the (authorized) server sending the request
+func (h *Handler) HandlePOST(c echo.Context) error {
+	s, ok := serverauth.FromContext(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusForbidden).SetInternal(fmt.Errorf("request not authorized by server"))
+	}
+
+	params := struct {
+		Start uint32 `json:"start" validate:"required"`
+		End   uint32 `json:"end" validate:"required,gtefield=Start"`
+		Map   uint16 `json:"map" validate:"field"`
+		Teams []struct {
+			ID      uint8  `json:"id" validate:"min=1,max=2"`
+			Army    uint8  `json:"army" validate:"army"`
+			Tickets uint16 `json:"tickets"`
+			Winner  bool   `json:"winner"`
+		} `json:"teams" validate:"max=2,dive"`
+		Players []struct {
+			PID          uint32 `json:"pid" validate:"required"`
+			Team         uint8  `json:"team" validate:"min=1,max=2"`
+			Score        int    `json:"score"`
+			CommandScore int    `json:"commandScore"`
+			CombatScore  int    `json:"combatScore"`
+			TeamScore    int    `json:"teamScore"`
+			Kills        uint32 `json:"kills"`
+			Deaths       uint32 `json:"deaths"`
+			Time         uint32 `json:"time"`
+			CommandTime  uint32 `json:"commandTime"`
+			Kits         []struct {
+				ID     uint8  `json:"id" validate:"kit"`
+				Time   uint32 `json:"time"`
+				Score  int    `json:"score"`
+				Kills  uint32 `json:"kills"`
+				Deaths uint32 `json:"deaths"`
+			} `json:"kits" validate:"dive"`
+			Vehicles []struct {
+				ID        uint8  `json:"id" validate:"vehicle"`
+				Time      uint32 `json:"time"`
+				Score     int    `json:"score"`
+				Kills     uint32 `json:"kills"`
+				Deaths    uint32 `json:"deaths"`
+				RoadKills uint32 `json:"roadKills"`
+			} `json:"vehicles" validate:"dive"`
+			Weapons []struct {
+				ID            uint8  `json:"id" validate:"weapon"`
+				Time          uint32 `json:"time"`
+				Score         int    `json:"score"`
+				Kills         uint32 `json:"kills"`
+				Deaths        uint32 `json:"deaths"`
+				ShotsFired    uint32 `json:"shotsFired"`
+				ShotsHit      uint32 `json:"shotsHit"`
+				TimesDeployed uint32 `json:"timesDeployed"`
+			} `json:"weapons" validate:"dive"`
+		} `json:"players" validate:"dive"`
+	}{}
+
+	if err := request.Bind(c, &params); err != nil {
+		return err
+	}
+
+	history := round.History{
+		Summary: round.Summary{
+			Start:  params.Start,
+			End:    params.End,
+			Field:  round.FieldRef{ID: params.Map},
+			Server: round.ServerRef{ID: s.ID},
+			Teams:  make([]round.Team, 0, len(params.Teams)),
+		},
+		Players: make([]round.PlayerRecord, 0, len(params.Players)),
+	}
+	for _, t := range params.Teams {
+		history.Teams = append(history.Teams, round.Team{
+			ID:      t.ID,
+			Army:    round.ArmyRef{ID: t.Army},
+			Tickets: t.Tickets,
+			Winner:  t.Winner,
+		})
+	}
+	for _, p := range params.Players {
+		record := round.PlayerRecord{
+			Player:       round.PlayerStub{ID: p.PID},
+			Team:         p.Team,
+			Score:        p.Score,
+			CommandScore: p.CommandScore,
+			CombatScore:  p.CombatScore,
+			TeamScore:    p.TeamScore,
+			Kills:        p.Kills,
+			Deaths:       p.Deaths,
+			Time:         p.Time,
+			CommandTime:  p.CommandTime,
+			Kits:         make([]round.KitRecord, 0, len(p.Kits)),
+			Vehicles:     make([]round.VehicleRecord, 0, len(p.Vehicles)),
+			Weapons:      make([]round.WeaponRecord, 0, len(p.Weapons)),
+		}
+		for _, k := range p.Kits {
+			record.Kits = append(record.Kits, round.KitRecord{
+				Kit:    round.KitRef{ID: k.ID},
+				Time:   k.Time,
+				Score:  k.Score,
+				Kills:  k.Kills,
+				Deaths: k.Deaths,
+			})
+		}
+		for _, v := range p.Vehicles {
+			record.Vehicles = append(record.Vehicles, round.VehicleRecord{
+				Vehicle:   round.VehicleRef{ID: v.ID},
+				Time:      v.Time,
+				Score:     v.Score,
+				Kills:     v.Kills,
+				Deaths:    v.Deaths,
+				RoadKills: v.RoadKills,
+			})
+		}
+		for _, w := range p.Weapons {
+			record.Weapons = append(record.Weapons, round.WeaponRecord{
+				Weapon:        round.WeaponRef{ID: w.ID},
+				Time:          w.Time,
+				Score:         w.Score,
+				Kills:         w.Kills,
+				Deaths:        w.Deaths,
+				ShotsFired:    w.ShotsFired,
+				ShotsHit:      w.ShotsHit,
+				TimesDeployed: w.TimesDeployed,
+			})
+		}
+		history.Players = append(history.Players, record)
+	}
+
+	id, err := h.roundHistoryRepository.Insert(c.Request().Context(), history)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to insert round: %w", err))
+	}
+
+	playerIDs := make([]uint32, 0, len(history.Players))
+	for _, p := range history.Players {
+		playerIDs = append(playerIDs, p.Player.ID)
+	}
+
+	// Round has been recorded at this point, so failing to publish should not fail the request
+	err = h.publisher.Publish(c.Request().Context(), event.Event{
+		Type: event.TypeRoundIngested,
+		// Will overflow on 7 February 2106 at 06:28:15 UTC
+		Timestamp: uint32(time.Now().UTC().Unix()),
+		Round: &event.RoundIngested{
+			ID:        id,
+			ServerID:  s.ID,
+			FieldID:   params.Map,
+			PlayerIDs: playerIDs,
+		},
+	})
+	if err != nil {
+		log.Ctx(c.Request().Context()).Error().
+			Err(err).
+			Uint32("round", id).
+			Msg("Failed to publish round ingested event")
+	}
+
+	return c.JSON(http.StatusCreated, createdDTO{ID: id})
+}
+
+func toRoundDTO(summary round.Summary) roundDTO {
+	teams := make([]teamDTO, 0, len(summary.Teams))
+	for _, team := range summary.Teams {
+		teams = append(teams, teamDTO{
+			ID:      team.ID,
+			Army:    team.Army.ID,
+			Tickets: team.Tickets,
+			Winner:  team.Winner,
+		})
+	}
+
+	return roundDTO{
+		ID:       summary.ID,
+		Start:    summary.Start,
+		End:      summary.End,
+		Duration: summary.Duration(),
+		Map:      summary.Field.ID,
+		Server: serverDTO{
+			ID:   summary.Server.ID,
+			Name: summary.Server.Name,
+		},
+		Teams: teams,
+	}
+}
+
+func toPlayerRecordDTO(record round.PlayerRecord) playerRecordDTO {
+	kits := make([]kitRecordDTO, 0, len(record.Kits))
+	for _, kit := range record.Kits {
+		kits = append(kits, kitRecordDTO{
+			ID:     kit.Kit.ID,
+			Time:   kit.Time,
+			Score:  kit.Score,
+			Kills:  kit.Kills,
+			Deaths: kit.Deaths,
+		})
+	}
+
+	return playerRecordDTO{
+		PID:          record.Player.ID,
+		Name:         record.Player.Name,
+		Team:         record.Team,
+		Score:        record.Score,
+		CommandScore: record.CommandScore,
+		CombatScore:  record.CombatScore,
+		TeamScore:    record.TeamScore,
+		Kills:        record.Kills,
+		Deaths:       record.Deaths,
+		Time:         record.Time,
+		CommandTime:  record.CommandTime,
+		Kits:         kits,
+	}
+}
@@ -0,0 +1,47 @@
+package season
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cetteup/gasp/internal/domain/season"
+)
+
+type Handler struct {
+	seasonRepository season.Repository
+}
+
+func NewHandler(seasonRepository season.Repository) *Handler {
+	return &Handler{
+		seasonRepository: seasonRepository,
+	}
+}
+
+type seasonDTO struct {
+	ID    uint32 `json:"id"`
+	Name  string `json:"name"`
+	Start uint32 `json:"start"`
+	// End Zero for the running season
+	End uint32 `json:"end"`
+}
+
+func (h *Handler) HandleGET(c echo.Context) error {
+	seasons, err := h.seasonRepository.FindAll(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find seasons: %w", err))
+	}
+
+	dtos := make([]seasonDTO, 0, len(seasons))
+	for _, s := range seasons {
+		dtos = append(dtos, seasonDTO{
+			ID:    s.ID,
+			Name:  s.Name,
+			Start: s.Start,
+			End:   s.End,
+		})
+	}
+
+	return c.JSON(http.StatusOK, dtos)
+}
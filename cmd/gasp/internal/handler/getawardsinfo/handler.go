@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
 	"github.com/cetteup/gasp/internal/domain/award"
 	"github.com/cetteup/gasp/internal/util"
 	"github.com/cetteup/gasp/pkg/asp"
@@ -27,12 +27,8 @@ func (h *Handler) HandleGET(c echo.Context) error {
 		PID uint32 `query:"pid" validate:"required"`
 	}{}
 
-	if err := c.Bind(&params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("failed to bind request parameters: %w", err))
-	}
-
-	if err := validator.New().StructCtx(c.Request().Context(), params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("invalid parameters: %w", err))
+	if err := request.Bind(c, &params); err != nil {
+		return err
 	}
 
 	records, err := h.awardRecordRepository.FindByPlayerID(c.Request().Context(), params.PID)
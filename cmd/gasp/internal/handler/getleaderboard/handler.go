@@ -6,38 +6,51 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 
 	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getleaderboard/internal/gather"
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/catalogue"
 	"github.com/cetteup/gasp/internal/domain/leaderboard"
+	"github.com/cetteup/gasp/internal/domain/season"
 	"github.com/cetteup/gasp/internal/util"
 	"github.com/cetteup/gasp/pkg/asp"
 )
 
 type Gatherer interface {
-	Gather(ctx context.Context, t, id string, position, before, after uint32, pid *uint32) (gather.GatheredData, error)
+	Gather(ctx context.Context, t, id, by string, position, before, after uint32, pid *uint32, country, window string) (gather.GatheredData, error)
 }
 
 type Handler struct {
 	gatherer Gatherer
 }
 
-func NewHandler(leaderboardRepository leaderboard.Repository) *Handler {
+func NewHandler(
+	leaderboardRepository leaderboard.Repository,
+	seasonRepository season.Repository,
+	catalogue *catalogue.Catalogue,
+) *Handler {
 	return &Handler{
 		// Gatherer is "hidden" to only pass repositories to handlers (completely arbitrary design decision)
-		gatherer: gather.NewGatherer(leaderboardRepository),
+		gatherer: gather.NewGatherer(leaderboardRepository, seasonRepository, catalogue),
 	}
 }
 
 func (h *Handler) HandleGET(c echo.Context) error {
 	params := struct {
-		Type     string  `query:"type" validate:"required,oneof=score kit vehicle weapon risingstar"`
-		ID       string  `query:"id" validate:"required_unless=Type risingstar,omitempty,oneof=overall combat commander team 0 1 2 3 4 5 6 7 8"`
+		Type string `query:"type" validate:"required,oneof=score kit vehicle weapon risingstar army map"`
+		// ID Valid ids depend on the type and are validated by the gatherer (map ids would be too many to list here)
+		ID string `query:"id" validate:"required_unless=Type risingstar,omitempty,alphanum"`
+		// By Not part of the original API, stat to rank army (score, wins) and map (wins, time) leaderboards by
+		By       string  `query:"by" validate:"omitempty,oneof=score wins time"`
 		Position uint32  `query:"pos"`
 		Before   uint32  `query:"before"`
 		After    uint32  `query:"after"`
 		PID      *uint32 `query:"pid"`
+		// Country Not part of the original API, restricts any leaderboard to players from the given country
+		Country string `query:"country" validate:"omitempty,len=2,alpha"`
+		// Window Not part of the original API, ranks score/kit/vehicle/weapon stats of the current day/week/month/season only
+		Window string `query:"window" validate:"omitempty,oneof=day week month season"`
 	}{
 		// Default values
 		Position: 1,
@@ -45,26 +58,27 @@ func (h *Handler) HandleGET(c echo.Context) error {
 		After:    19,
 	}
 
-	if err := c.Bind(&params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("failed to bind request parameters: %w", err))
-	}
-
-	if err := validator.New().StructCtx(c.Request().Context(), params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("invalid parameters: %w", err))
+	if err := request.Bind(c, &params); err != nil {
+		return err
 	}
 
 	data, err := h.gatherer.Gather(
 		c.Request().Context(),
 		params.Type,
 		params.ID,
+		params.By,
 		params.Position,
 		params.Before,
 		params.After,
 		params.PID,
+		params.Country,
+		params.Window,
 	)
 	if err != nil {
-		if errors.Is(err, gather.ErrInvalidLeaderboardType) || errors.Is(err, gather.ErrInvalidLeaderboardID) {
-			return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
+		if errors.Is(err, gather.ErrInvalidLeaderboardType) || errors.Is(err, gather.ErrInvalidLeaderboardID) ||
+			errors.Is(err, gather.ErrInvalidLeaderboardBy) ||
+			errors.Is(err, gather.ErrInvalidLeaderboardWindow) {
+			return request.NewInvalidParametersError(err)
 		}
 		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to gather data: %w", err))
 	}
@@ -3,15 +3,14 @@ package gather
 import (
 	"context"
 	"errors"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/cetteup/gasp/cmd/gasp/internal/handler/internal/dto"
-	"github.com/cetteup/gasp/internal/domain/kit"
+	"github.com/cetteup/gasp/internal/catalogue"
 	"github.com/cetteup/gasp/internal/domain/leaderboard"
-	"github.com/cetteup/gasp/internal/domain/vehicle"
-	"github.com/cetteup/gasp/internal/domain/weapon"
+	"github.com/cetteup/gasp/internal/domain/season"
 	"github.com/cetteup/gasp/internal/util"
 )
 
@@ -21,16 +20,29 @@ const (
 	typeVehicle    = "vehicle"
 	typeWeapon     = "weapon"
 	typeRisingStar = "risingstar"
+	typeArmy       = "army"
+	typeField      = "map"
 
 	scoreIDOverall = "overall"
 	scoreIDCombat  = "combat"
 	scoreIDCommand = "commander"
 	scoreIDTeam    = "team"
+
+	byScore = "score"
+	byWins  = "wins"
+	byTime  = "time"
+
+	windowDay    = "day"
+	windowWeek   = "week"
+	windowMonth  = "month"
+	windowSeason = "season"
 )
 
 var (
-	ErrInvalidLeaderboardType = errors.New("invalid leaderboard type")
-	ErrInvalidLeaderboardID   = errors.New("invalid leaderboard id")
+	ErrInvalidLeaderboardType   = errors.New("invalid leaderboard type")
+	ErrInvalidLeaderboardID     = errors.New("invalid leaderboard id")
+	ErrInvalidLeaderboardBy     = errors.New("invalid leaderboard by")
+	ErrInvalidLeaderboardWindow = errors.New("invalid leaderboard window")
 )
 
 type GatheredData struct {
@@ -42,15 +54,23 @@ type GatheredData struct {
 
 type Gatherer struct {
 	leaderboardRepository leaderboard.Repository
+	seasonRepository      season.Repository
+	catalogue             *catalogue.Catalogue
 }
 
-func NewGatherer(leaderboardRepository leaderboard.Repository) *Gatherer {
+func NewGatherer(
+	leaderboardRepository leaderboard.Repository,
+	seasonRepository season.Repository,
+	catalogue *catalogue.Catalogue,
+) *Gatherer {
 	return &Gatherer{
 		leaderboardRepository: leaderboardRepository,
+		seasonRepository:      seasonRepository,
+		catalogue:             catalogue,
 	}
 }
 
-func (g *Gatherer) Gather(ctx context.Context, t, id string, position, before, after uint32, pid *uint32) (GatheredData, error) {
+func (g *Gatherer) Gather(ctx context.Context, t, id, by string, position, before, after uint32, pid *uint32, country, window string) (GatheredData, error) {
 	var filter leaderboard.Filter
 	if pid != nil {
 		filter = leaderboard.NewPIDFilter(*pid)
@@ -60,6 +80,20 @@ func (g *Gatherer) Gather(ctx context.Context, t, id string, position, before, a
 		last := min(position+after, first+20)
 		filter = leaderboard.NewPositionFilter(first, last)
 	}
+	filter.Country = country
+
+	if window != "" {
+		// Only rounds' score/kit/vehicle/weapon stats are recorded
+		if t != typeScore && t != typeKit && t != typeVehicle && t != typeWeapon {
+			return GatheredData{}, ErrInvalidLeaderboardWindow
+		}
+
+		since, err := g.getWindowStart(ctx, window)
+		if err != nil {
+			return GatheredData{}, err
+		}
+		filter.Since = since
+	}
 
 	// GameSpy had a handy breakdown of the available leaderboards and their parameters
 	// See https://web.archive.org/web/20130615060443/http://bf2web.gamespy.com/ASP/getleaderboard.aspx
@@ -74,6 +108,11 @@ func (g *Gatherer) Gather(ctx context.Context, t, id string, position, before, a
 		return g.gatherWeaponData(ctx, id, filter)
 	case typeRisingStar:
 		return g.gatherRisingStarData(ctx, filter)
+	// Army and map leaderboards were not offered by GameSpy
+	case typeArmy:
+		return g.gatherArmyData(ctx, id, by, filter)
+	case typeField:
+		return g.gatherFieldData(ctx, id, by, filter)
 	default:
 		return GatheredData{}, ErrInvalidLeaderboardType
 	}
@@ -121,17 +160,22 @@ func (g *Gatherer) gatherScoreData(ctx context.Context, id string, filter leader
 		})
 	}
 
+	// Leaderboards may be served from a snapshot, so "asof" is not necessarily now
+	timestamp, err := g.leaderboardRepository.GetUpdateTimestamp(ctx, filter)
+	if err != nil {
+		return GatheredData{}, err
+	}
+
 	return GatheredData{
 		Keys:    keys,
 		Entries: formatted,
 		Size:    size,
-		// Will overflow on 7 February 2106 at 06:28:15 UTC
-		AsOf: uint32(time.Now().UTC().Unix()),
+		AsOf:    timestamp,
 	}, nil
 }
 
 func (g *Gatherer) gatherKitData(ctx context.Context, id string, filter leaderboard.Filter) (GatheredData, error) {
-	kitID, err := toKitID(id)
+	kitID, err := toID(id, g.catalogue.KitIDs())
 	if err != nil {
 		return GatheredData{}, err
 	}
@@ -160,17 +204,22 @@ func (g *Gatherer) gatherKitData(ctx context.Context, id string, filter leaderbo
 		})
 	}
 
+	// Leaderboards may be served from a snapshot, so "asof" is not necessarily now
+	timestamp, err := g.leaderboardRepository.GetUpdateTimestamp(ctx, filter)
+	if err != nil {
+		return GatheredData{}, err
+	}
+
 	return GatheredData{
 		Keys:    keys,
 		Entries: formatted,
 		Size:    size,
-		// Will overflow on 7 February 2106 at 06:28:15 UTC
-		AsOf: uint32(time.Now().UTC().Unix()),
+		AsOf:    timestamp,
 	}, nil
 }
 
 func (g *Gatherer) gatherVehicleData(ctx context.Context, id string, filter leaderboard.Filter) (GatheredData, error) {
-	vehicleID, err := toVehicleID(id)
+	vehicleID, err := toID(id, g.catalogue.VehicleLeaderboardIDs())
 	if err != nil {
 		return GatheredData{}, err
 	}
@@ -199,17 +248,22 @@ func (g *Gatherer) gatherVehicleData(ctx context.Context, id string, filter lead
 		})
 	}
 
+	// Leaderboards may be served from a snapshot, so "asof" is not necessarily now
+	timestamp, err := g.leaderboardRepository.GetUpdateTimestamp(ctx, filter)
+	if err != nil {
+		return GatheredData{}, err
+	}
+
 	return GatheredData{
 		Keys:    keys,
 		Entries: formatted,
 		Size:    size,
-		// Will overflow on 7 February 2106 at 06:28:15 UTC
-		AsOf: uint32(time.Now().UTC().Unix()),
+		AsOf:    timestamp,
 	}, nil
 }
 
 func (g *Gatherer) gatherWeaponData(ctx context.Context, id string, filter leaderboard.Filter) (GatheredData, error) {
-	weaponID, err := toWeaponID(id)
+	weaponID, err := toID(id, g.catalogue.WeaponLeaderboardIDs())
 	if err != nil {
 		return GatheredData{}, err
 	}
@@ -239,12 +293,17 @@ func (g *Gatherer) gatherWeaponData(ctx context.Context, id string, filter leade
 		})
 	}
 
+	// Leaderboards may be served from a snapshot, so "asof" is not necessarily now
+	timestamp, err := g.leaderboardRepository.GetUpdateTimestamp(ctx, filter)
+	if err != nil {
+		return GatheredData{}, err
+	}
+
 	return GatheredData{
 		Keys:    keys,
 		Entries: formatted,
 		Size:    size,
-		// Will overflow on 7 February 2106 at 06:28:15 UTC
-		AsOf: uint32(time.Now().UTC().Unix()),
+		AsOf:    timestamp,
 	}, nil
 }
 
@@ -270,7 +329,7 @@ func (g *Gatherer) gatherRisingStarData(ctx context.Context, filter leaderboard.
 		})
 	}
 
-	// While all other leaderboards are always "asof" now, the rising star leaderboard is only updated manually
+	// While all other leaderboards are "asof" now (or their last snapshot), the rising star leaderboard is only updated manually
 	// due to how heavy of a computation it is.
 	timestamp, err := g.leaderboardRepository.GetRisingStarUpdateTimestamp(ctx)
 	if err != nil {
@@ -285,6 +344,97 @@ func (g *Gatherer) gatherRisingStarData(ctx context.Context, filter leaderboard.
 	}, nil
 }
 
+func (g *Gatherer) gatherArmyData(ctx context.Context, id, by string, filter leaderboard.Filter) (GatheredData, error) {
+	armyID, err := toID(id, g.catalogue.ArmyIDs())
+	if err != nil {
+		return GatheredData{}, err
+	}
+
+	rankBy, err := toArmyRankBy(by)
+	if err != nil {
+		return GatheredData{}, err
+	}
+
+	entries, size, err := g.leaderboardRepository.FindTopPlayersByArmy(ctx, armyID, rankBy, filter)
+	if err != nil {
+		return GatheredData{}, err
+	}
+
+	keys := []string{"n", "pid", "nick", "score", "wins", "losses", "timeused", "playerrank", "countrycode"}
+	formatted := make([]map[string]string, 0, len(entries))
+	for _, entry := range entries {
+		formatted = append(formatted, map[string]string{
+			"n":           util.FormatUint(entry.Position),
+			"pid":         util.FormatUint(entry.Data.Player.ID),
+			"nick":        entry.Data.Player.Name,
+			"score":       util.FormatInt(entry.Data.Score),
+			"wins":        util.FormatUint(entry.Data.Wins),
+			"losses":      util.FormatUint(entry.Data.Losses),
+			"timeused":    util.FormatUint(entry.Data.Time),
+			"playerrank":  util.FormatUint(entry.Data.Player.Rank.ID),
+			"countrycode": strings.ToUpper(entry.Data.Player.Country),
+		})
+	}
+
+	// Leaderboards may be served from a snapshot, so "asof" is not necessarily now
+	timestamp, err := g.leaderboardRepository.GetUpdateTimestamp(ctx, filter)
+	if err != nil {
+		return GatheredData{}, err
+	}
+
+	return GatheredData{
+		Keys:    keys,
+		Entries: formatted,
+		Size:    size,
+		AsOf:    timestamp,
+	}, nil
+}
+
+func (g *Gatherer) gatherFieldData(ctx context.Context, id, by string, filter leaderboard.Filter) (GatheredData, error) {
+	fieldID, err := g.toFieldID(id)
+	if err != nil {
+		return GatheredData{}, err
+	}
+
+	rankBy, err := toFieldRankBy(by)
+	if err != nil {
+		return GatheredData{}, err
+	}
+
+	entries, size, err := g.leaderboardRepository.FindTopPlayersByField(ctx, fieldID, rankBy, filter)
+	if err != nil {
+		return GatheredData{}, err
+	}
+
+	keys := []string{"n", "pid", "nick", "wins", "losses", "timeused", "playerrank", "countrycode"}
+	formatted := make([]map[string]string, 0, len(entries))
+	for _, entry := range entries {
+		formatted = append(formatted, map[string]string{
+			"n":           util.FormatUint(entry.Position),
+			"pid":         util.FormatUint(entry.Data.Player.ID),
+			"nick":        entry.Data.Player.Name,
+			"wins":        util.FormatUint(entry.Data.Wins),
+			"losses":      util.FormatUint(entry.Data.Losses),
+			"timeused":    util.FormatUint(entry.Data.Time),
+			"playerrank":  util.FormatUint(entry.Data.Player.Rank.ID),
+			"countrycode": strings.ToUpper(entry.Data.Player.Country),
+		})
+	}
+
+	// Leaderboards may be served from a snapshot, so "asof" is not necessarily now
+	timestamp, err := g.leaderboardRepository.GetUpdateTimestamp(ctx, filter)
+	if err != nil {
+		return GatheredData{}, err
+	}
+
+	return GatheredData{
+		Keys:    keys,
+		Entries: formatted,
+		Size:    size,
+		AsOf:    timestamp,
+	}, nil
+}
+
 func toScoreType(id string) (leaderboard.ScoreType, error) {
 	switch id {
 	case scoreIDOverall:
@@ -300,87 +450,85 @@ func toScoreType(id string) (leaderboard.ScoreType, error) {
 	}
 }
 
-func toKitID(id string) (uint8, error) {
+// toID Kit, vehicle, weapon and army ids are identical between the ASP and the database
+func toID(id string, valid []uint8) (uint8, error) {
 	i, err := strconv.ParseUint(id, 10, 8)
 	if err != nil {
 		return 0, ErrInvalidLeaderboardID
 	}
 
 	// We parse with bit size, so casting from uint64 to uint8 is safe here
-	switch uint8(i) {
-	case dto.KitAntiTank:
-		return kit.AntiTank, nil
-	case dto.KitAssault:
-		return kit.Assault, nil
-	case dto.KitEngineer:
-		return kit.Engineer, nil
-	case dto.KitMedic:
-		return kit.Medic, nil
-	case dto.KitSpecOps:
-		return kit.SpecOps, nil
-	case dto.KitSupport:
-		return kit.Support, nil
-	case dto.KitSniper:
-		return kit.Sniper, nil
-	default:
+	// Not all vehicles and weapons are supported
+	if !slices.Contains(valid, uint8(i)) {
 		return 0, ErrInvalidLeaderboardID
 	}
+
+	return uint8(i), nil
 }
 
-func toVehicleID(id string) (uint8, error) {
-	i, err := strconv.ParseUint(id, 10, 8)
+func (g *Gatherer) toFieldID(id string) (uint16, error) {
+	i, err := strconv.ParseUint(id, 10, 16)
 	if err != nil {
 		return 0, ErrInvalidLeaderboardID
 	}
 
-	// We parse with bit size, so casting from uint64 to uint8 is safe here
-	switch uint8(i) {
-	case dto.VehicleArmor:
-		return vehicle.Armor, nil
-	case dto.VehicleJet:
-		return vehicle.Jet, nil
-	case dto.VehicleAirDefense:
-		return vehicle.AirDefense, nil
-	case dto.VehicleHelicopter:
-		return vehicle.Helicopter, nil
-	case dto.VehicleTransport:
-		return vehicle.Transport, nil
-	case dto.VehicleGroundDefense:
-		return vehicle.GroundDefense, nil
-	default:
-		// Not all vehicles are supported
+	// We parse with bit size, so casting from uint64 to uint16 is safe here
+	// Field ids may differ between the ASP and the database
+	fieldID, ok := g.catalogue.FieldFromASP(uint16(i))
+	if !ok {
 		return 0, ErrInvalidLeaderboardID
 	}
+
+	return fieldID, nil
 }
 
-func toWeaponID(id string) (uint8, error) {
-	i, err := strconv.ParseUint(id, 10, 8)
-	if err != nil {
-		return 0, ErrInvalidLeaderboardID
+func toArmyRankBy(by string) (leaderboard.ArmyRankBy, error) {
+	switch by {
+	case "", byScore:
+		return leaderboard.ArmyRankByScore, nil
+	case byWins:
+		return leaderboard.ArmyRankByWins, nil
+	default:
+		return 0, ErrInvalidLeaderboardBy
 	}
+}
 
-	// We parse with bit size, so casting from uint64 to uint8 is safe here
-	switch uint8(i) {
-	case dto.WeaponAssaultRifle:
-		return weapon.AssaultRifle, nil
-	case dto.WeaponAssaultGrenade:
-		return weapon.AssaultGrenade, nil
-	case dto.WeaponCarbine:
-		return weapon.Carbine, nil
-	case dto.WeaponLightMachineGun:
-		return weapon.LightMachineGun, nil
-	case dto.WeaponSniperRifle:
-		return weapon.SniperRifle, nil
-	case dto.WeaponPistol:
-		return weapon.Pistol, nil
-	case dto.WeaponAntiTankAntiAir:
-		return weapon.AntiTankAntiAir, nil
-	case dto.WeaponSubMachineGun:
-		return weapon.SubMachineGun, nil
-	case dto.WeaponShotgun:
-		return weapon.Shotgun, nil
+func toFieldRankBy(by string) (leaderboard.FieldRankBy, error) {
+	switch by {
+	case "", byWins:
+		return leaderboard.FieldRankByWins, nil
+	case byTime:
+		return leaderboard.FieldRankByTime, nil
 	default:
-		// Not all weapons are supported
-		return 0, ErrInvalidLeaderboardID
+		return 0, ErrInvalidLeaderboardBy
+	}
+}
+
+func (g *Gatherer) getWindowStart(ctx context.Context, window string) (uint32, error) {
+	switch window {
+	case windowDay:
+		return leaderboard.WindowDay.Since(time.Now()), nil
+	case windowWeek:
+		return leaderboard.WindowWeek.Since(time.Now()), nil
+	case windowMonth:
+		return leaderboard.WindowMonth.Since(time.Now()), nil
+	case windowSeason:
+		// Use the season the request is scoped to, falling back to the running season
+		var s season.Season
+		var err error
+		if id, ok := season.FromContext(ctx); ok {
+			s, err = g.seasonRepository.FindByID(ctx, id)
+		} else {
+			s, err = g.seasonRepository.FindCurrent(ctx)
+		}
+		if err != nil {
+			if errors.Is(err, season.ErrSeasonNotFound) {
+				return 0, ErrInvalidLeaderboardWindow
+			}
+			return 0, err
+		}
+		return s.Start, nil
+	default:
+		return 0, ErrInvalidLeaderboardWindow
 	}
 }
@@ -1,15 +1,18 @@
 package getplayerinfo
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 
 	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getplayerinfo/internal/gather"
 	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getplayerinfo/internal/info"
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/cache"
+	"github.com/cetteup/gasp/internal/catalogue"
 	"github.com/cetteup/gasp/internal/domain/army"
 	"github.com/cetteup/gasp/internal/domain/field"
 	"github.com/cetteup/gasp/internal/domain/kill"
@@ -20,8 +23,13 @@ import (
 	"github.com/cetteup/gasp/pkg/asp"
 )
 
+type Gatherer interface {
+	Gather(ctx context.Context, pid uint32, keys []string) (map[string]string, error)
+}
+
 type Handler struct {
-	gatherer *gather.Gatherer
+	gatherer  Gatherer
+	catalogue *catalogue.Catalogue
 }
 
 func NewHandler(
@@ -32,41 +40,64 @@ func NewHandler(
 	kitRecordRepository kit.RecordRepository,
 	vehicleRecordRepository vehicle.RecordRepository,
 	weaponRecordRepository weapon.RecordRepository,
+	catalogue *catalogue.Catalogue,
+	store cache.Store,
 ) *Handler {
+	// Gatherer is "hidden" to only pass repositories to handlers (completely arbitrary design decision)
+	var gatherer Gatherer = gather.NewGatherer(
+		playerRepository,
+		armyRecordRepository,
+		fieldRecordRepository,
+		killHistoryRecordRepository,
+		kitRecordRepository,
+		vehicleRecordRepository,
+		weaponRecordRepository,
+		catalogue,
+	)
+	// Caching is optional
+	if store != nil {
+		gatherer = gather.NewCachingGatherer(gatherer, store)
+	}
+
 	return &Handler{
-		// Gatherer is "hidden" to only pass repositories to handlers (completely arbitrary design decision)
-		gatherer: gather.NewGatherer(
-			playerRepository,
-			armyRecordRepository,
-			fieldRecordRepository,
-			killHistoryRecordRepository,
-			kitRecordRepository,
-			vehicleRecordRepository,
-			weaponRecordRepository,
-		),
+		gatherer:  gatherer,
+		catalogue: catalogue,
+	}
+}
+
+// InvalidatePlayer Drops any cached values for the given player (noop if caching is disabled)
+func (h *Handler) InvalidatePlayer(ctx context.Context, pid uint32) error {
+	if invalidator, ok := h.gatherer.(cache.PlayerInvalidator); ok {
+		return invalidator.InvalidatePlayer(ctx, pid)
+	}
+	return nil
+}
+
+// InvalidateStats Drops all cached values (noop if caching is disabled)
+func (h *Handler) InvalidateStats(ctx context.Context) error {
+	if invalidator, ok := h.gatherer.(cache.StatsInvalidator); ok {
+		return invalidator.InvalidateStats(ctx)
 	}
+	return nil
 }
 
 func (h *Handler) HandleGET(c echo.Context) error {
 	params := struct {
-		PID     uint32  `query:"pid" validate:"required"`
-		Info    string  `query:"info" validate:"required"`
-		Field   *uint16 `query:"map" validate:"omitempty,oneof=0 1 2 3 4 5 6 10 11 12 100 101 102 103 104 105 110 120 200 201 202 300 301 302 303 304 305 306 307 601 602"`
-		Kit     *uint8  `query:"kit" validate:"omitempty,oneof=0 1 2 3 4 5 6"`
-		Vehicle *uint8  `query:"vehicle" validate:"omitempty,oneof=0 1 2 3 4 5 6"`
-		Weapon  *uint8  `query:"weapon" validate:"omitempty,oneof=0 1 2 3 4 5 6 7 8 9 10 11 12 13"`
+		PID  uint32 `query:"pid" validate:"required"`
+		Info string `query:"info" validate:"required"`
+		// Field, Kit, Vehicle, Weapon Valid ids depend on the mod, so they are validated against the realm's catalogue
+		Field   *uint16 `query:"map" validate:"omitempty,aspfield"`
+		Kit     *uint8  `query:"kit" validate:"omitempty,kit"`
+		Vehicle *uint8  `query:"vehicle" validate:"omitempty,vehicle"`
+		Weapon  *uint8  `query:"weapon" validate:"omitempty,aspweapon"`
 	}{}
 
-	if err := c.Bind(&params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("failed to bind request parameters: %w", err))
-	}
-
-	if err := validator.New().StructCtx(c.Request().Context(), params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("invalid parameters: %w", err))
+	if err := request.Bind(c, &params); err != nil {
+		return err
 	}
 
 	// Info query may contain wildcard "groups" such as "cmb*", which need to be resolved to the underlying keys
-	opts := info.NewResolveOptions().
+	opts := info.NewResolveOptions(h.catalogue).
 		// Add player id and name as defaults, since those should *always* be the first two keys in the response
 		SetDefaultKeys(info.KeyID, info.KeyName).
 		// Maybe only replaces the default values if at least one value is non-nil
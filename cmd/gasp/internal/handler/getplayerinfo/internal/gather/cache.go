@@ -0,0 +1,62 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/cetteup/gasp/internal/cache"
+	"github.com/cetteup/gasp/internal/domain/season"
+)
+
+const (
+	keyPrefix = "playerinfo:"
+)
+
+type gatherer interface {
+	Gather(ctx context.Context, pid uint32, keys []string) (map[string]string, error)
+}
+
+// CachingGatherer Caches values gathered by the wrapped gatherer per player and set of keys
+type CachingGatherer struct {
+	gatherer gatherer
+	store    cache.Store
+}
+
+func NewCachingGatherer(gatherer gatherer, store cache.Store) *CachingGatherer {
+	return &CachingGatherer{
+		gatherer: gatherer,
+		store:    store,
+	}
+}
+
+func (g *CachingGatherer) Gather(ctx context.Context, pid uint32, keys []string) (map[string]string, error) {
+	return cache.GetOrLoad(ctx, g.store, buildKey(ctx, pid, keys), func(ctx context.Context) (map[string]string, error) {
+		return g.gatherer.Gather(ctx, pid, keys)
+	})
+}
+
+func (g *CachingGatherer) InvalidatePlayer(ctx context.Context, pid uint32) error {
+	return g.store.DeletePrefix(ctx, buildPlayerKeyPrefix(pid))
+}
+
+func (g *CachingGatherer) InvalidateStats(ctx context.Context) error {
+	return g.store.DeletePrefix(ctx, keyPrefix)
+}
+
+func buildKey(ctx context.Context, pid uint32, keys []string) string {
+	// BFHQ requests contain 200+ keys, so hash them rather than using them as part of the key directly
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strings.Join(keys, ",")))
+
+	// Archived seasons' stats need to be cached separately from the live stats
+	if s, ok := season.FromContext(ctx); ok {
+		return fmt.Sprintf("%sseason:%d:%x", buildPlayerKeyPrefix(pid), s, h.Sum64())
+	}
+	return fmt.Sprintf("%s%x", buildPlayerKeyPrefix(pid), h.Sum64())
+}
+
+func buildPlayerKeyPrefix(pid uint32) string {
+	return fmt.Sprintf("%s%d:", keyPrefix, pid)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getplayerinfo/internal/info"
 	"github.com/cetteup/gasp/cmd/gasp/internal/handler/internal/dto"
+	"github.com/cetteup/gasp/internal/catalogue"
 	"github.com/cetteup/gasp/internal/constraints"
 	"github.com/cetteup/gasp/internal/domain/army"
 	"github.com/cetteup/gasp/internal/domain/field"
@@ -38,6 +39,7 @@ type Gatherer struct {
 	kitRecordRepository         kit.RecordRepository
 	vehicleRecordRepository     vehicle.RecordRepository
 	weaponRecordRepository      weapon.RecordRepository
+	catalogue                   *catalogue.Catalogue
 }
 
 func NewGatherer(
@@ -48,6 +50,7 @@ func NewGatherer(
 	kitRecordRepository kit.RecordRepository,
 	vehicleRecordRepository vehicle.RecordRepository,
 	weaponRecordRepository weapon.RecordRepository,
+	catalogue *catalogue.Catalogue,
 ) *Gatherer {
 	return &Gatherer{
 		playerRepository:            playerRepository,
@@ -57,6 +60,7 @@ func NewGatherer(
 		kitRecordRepository:         kitRecordRepository,
 		vehicleRecordRepository:     vehicleRecordRepository,
 		weaponRecordRepository:      weaponRecordRepository,
+		catalogue:                   catalogue,
 	}
 }
 
@@ -176,7 +180,7 @@ func (g *Gatherer) gatherArmyRecordData(pid uint32, b *sync.Map[string, string])
 
 		// Records are added "lazily", so we may only have records for some armies
 		// Since we cannot leave any gaps, we fill every item with record data or zeroes
-		for _, id := range army.IDs {
+		for _, id := range g.catalogue.ArmyIDs() {
 			// If id is not present in catalog, we get a zero entry - which is perfect
 			record := catalog[id]
 			suffix := util.FormatUint(id)
@@ -205,10 +209,10 @@ func (g *Gatherer) gatherFieldRecordData(pid uint32, b *sync.Map[string, string]
 		// Records are added "lazily", so we may only have records for some maps
 		// Since we cannot leave any gaps, we fill every item with record data or zeroes
 		var favorite field.Record
-		for _, id := range field.IDs {
+		for _, f := range g.catalogue.Fields {
 			// If id is not present in catalog, we get a zero entry - which is perfect
-			record := catalog[id]
-			suffix := util.FormatUint(fromDomainFieldID(id))
+			record := catalog[f.ID]
+			suffix := util.FormatUint(f.ASP())
 			b.Store(info.GroupFieldTime+suffix, util.FormatUint(record.Time))
 			b.Store(info.GroupFieldWins+suffix, util.FormatUint(record.Wins))
 			b.Store(info.GroupFieldLosses+suffix, util.FormatUint(record.Losses))
@@ -276,7 +280,7 @@ func (g *Gatherer) gatherKitRecordData(pid uint32, b *sync.Map[string, string])
 		// Records are added "lazily", so we may only have records for some kits
 		// Since we cannot leave any gaps, we fill every item with record data or zeroes
 		var favorite kit.Record
-		for _, id := range kit.IDs {
+		for _, id := range g.catalogue.KitIDs() {
 			// If id is not present in catalog, we get a zero entry - which is perfect
 			record := catalog[id]
 			suffix := util.FormatUint(id)
@@ -314,7 +318,7 @@ func (g *Gatherer) gatherVehicleRecordData(pid uint32, b *sync.Map[string, strin
 		var favorite vehicle.Record
 		// Using uint64 just to be safe, uint32 would probably be plenty
 		var roadKills uint64
-		for _, id := range vehicle.IDs {
+		for _, id := range g.catalogue.VehicleIDs() {
 			// If id is not present in catalog, we get a zero entry - which is perfect
 			record := catalog[id]
 			suffix := util.FormatUint(id)
@@ -359,37 +363,36 @@ func (g *Gatherer) gatherWeaponRecordData(pid uint32, b *sync.Map[string, string
 		// "Virtual" record, since explosives are grouped into one in ASP domain
 		explosives := weapon.Record{
 			Weapon: weapon.Weapon{
-				ID: dto.WeaponExplosives,
+				ID: g.catalogue.Weapons.Explosives,
 			},
 		}
+		var hasExplosives bool
 		// Using uint64 just to be safe, uint32 would probably be plenty
 		var shotsFired, shotsHit uint64
-		// Looping over backend domain weapon ids here, since we need all to be able to "translate" to ASP world
-		for _, id := range weapon.IDs {
+		// Looping over backend domain weapons here, since we need all to be able to "translate" to ASP world
+		for _, w := range g.catalogue.Weapons.Entries {
 			// If id is not present in catalog, we get a zero entry - which is perfect
-			record := catalog[id]
+			record := catalog[w.ID]
 
 			// Not using record.IsExplosive or record.IsEquipment here since we might be working based on zero-records
 			// from the catalog. Also, equipment means something else in the ASP world than in the internal domain.
-			if !isExplosiveID(id) && !isEquipmentID(id) {
-				suffix := util.FormatUint(id)
-				b.Store(info.GroupWeaponTime+suffix, util.FormatUint(record.Time))
-				b.Store(info.GroupWeaponKills+suffix, util.FormatUint(record.Kills))
-				b.Store(info.GroupWeaponDeaths+suffix, util.FormatUint(record.Deaths))
-				b.Store(info.GroupWeaponAccuracy+suffix, util.FormatUint(util.DivideUint(record.ShotsHit*100, record.ShotsFired)))
-				b.Store(info.GroupWeaponKillDeathRatio+suffix, formatRatio(ratio(record.Kills, record.Deaths)))
-			} else if isExplosiveID(id) {
+			switch {
+			case w.Explosive:
+				hasExplosives = true
 				explosives.Time += record.Time
 				explosives.Kills += record.Kills
 				explosives.Deaths += record.Deaths
 				explosives.ShotsFired += record.ShotsFired
 				explosives.ShotsHit += record.ShotsHit
-			} else {
-				equipmentID, err2 := weaponToEquipmentID(id)
-				if err2 != nil {
-					return err2
-				}
-				b.Store(info.GroupEquipmentTimesDeployed+util.FormatUint(equipmentID), util.FormatUint(record.TimesDeployed))
+			case w.Equipment != nil:
+				b.Store(info.GroupEquipmentTimesDeployed+util.FormatUint(*w.Equipment), util.FormatUint(record.TimesDeployed))
+			default:
+				suffix := util.FormatUint(w.ID)
+				b.Store(info.GroupWeaponTime+suffix, util.FormatUint(record.Time))
+				b.Store(info.GroupWeaponKills+suffix, util.FormatUint(record.Kills))
+				b.Store(info.GroupWeaponDeaths+suffix, util.FormatUint(record.Deaths))
+				b.Store(info.GroupWeaponAccuracy+suffix, util.FormatUint(util.DivideUint(record.ShotsHit*100, record.ShotsFired)))
+				b.Store(info.GroupWeaponKillDeathRatio+suffix, formatRatio(ratio(record.Kills, record.Deaths)))
 			}
 
 			// Add shots fired/hit
@@ -402,12 +405,19 @@ func (g *Gatherer) gatherWeaponRecordData(pid uint32, b *sync.Map[string, string
 			}
 		}
 
-		// Add cumulated explosives values and weapon 13 dummy (empty, but must be present)
-		for _, record := range []weapon.Record{explosives, {
-			Weapon: weapon.Weapon{
-				ID: dto.WeaponDummy,
-			},
-		}} {
+		// Add cumulated explosives values and dummies (empty, but must be present)
+		virtual := make([]weapon.Record, 0, len(g.catalogue.Weapons.Dummies)+1)
+		if hasExplosives {
+			virtual = append(virtual, explosives)
+		}
+		for _, id := range g.catalogue.Weapons.Dummies {
+			virtual = append(virtual, weapon.Record{
+				Weapon: weapon.Weapon{
+					ID: id,
+				},
+			})
+		}
+		for _, record := range virtual {
 			suffix := util.FormatUint(record.Weapon.ID)
 			b.Store(info.GroupWeaponTime+suffix, util.FormatUint(record.Time))
 			b.Store(info.GroupWeaponKills+suffix, util.FormatUint(record.Kills))
@@ -445,34 +455,3 @@ func ratio[A, B constraints.Integer](a A, b B) (int64, int64) {
 func formatRatio[A, B constraints.Integer](a A, b B) string {
 	return fmt.Sprintf("%d:%d", a, b)
 }
-
-func fromDomainFieldID(fieldID uint16) uint16 {
-	switch fieldID {
-	case field.OperationBluePearl:
-		// Currently mismatched in the database
-		return dto.FieldOperationBluePearl
-	default:
-		return fieldID
-	}
-}
-
-func isExplosiveID(weaponID uint8) bool {
-	return weaponID == weapon.C4 || weaponID == weapon.Claymore || weaponID == weapon.AntiTankMine
-}
-
-func isEquipmentID(weaponID uint8) bool {
-	return weaponID == weapon.GrapplingHook || weaponID == weapon.Zipline || weaponID == weapon.Tactical
-}
-
-func weaponToEquipmentID(weaponID uint8) (uint8, error) {
-	switch weaponID {
-	case weapon.GrapplingHook:
-		return dto.EquipmentGraplingHook, nil
-	case weapon.Zipline:
-		return dto.EquipmentZipline, nil
-	case weapon.Tactical:
-		return dto.EquipmentTactical, nil
-	default:
-		return 0, fmt.Errorf("equipment id not known for weapon id: %d", weaponID)
-	}
-}
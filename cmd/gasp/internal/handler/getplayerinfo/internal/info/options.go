@@ -1,7 +1,7 @@
 package info
 
 import (
-	"github.com/cetteup/gasp/cmd/gasp/internal/handler/internal/dto"
+	"github.com/cetteup/gasp/internal/catalogue"
 	"github.com/cetteup/gasp/internal/constraints"
 )
 
@@ -15,15 +15,16 @@ type ResolveOptions struct {
 	EquipmentIDs []uint8
 }
 
-func NewResolveOptions() *ResolveOptions {
+// NewResolveOptions Returns options defaulting to all ids known to the catalogue (as used by the ASP)
+func NewResolveOptions(c *catalogue.Catalogue) *ResolveOptions {
 	return &ResolveOptions{
 		DefaultKeys:  nil,
-		ArmyIDs:      dto.ArmyIDs,
-		FieldIDs:     dto.FieldIDs,
-		KitIDs:       dto.KitIDs,
-		VehicleIDs:   dto.VehicleIDs,
-		WeaponIDs:    dto.WeaponIDs,
-		EquipmentIDs: dto.EquipmentIDs,
+		ArmyIDs:      c.ArmyIDs(),
+		FieldIDs:     c.ASPFieldIDs(),
+		KitIDs:       c.KitIDs(),
+		VehicleIDs:   c.VehicleIDs(),
+		WeaponIDs:    c.ASPWeaponIDs(),
+		EquipmentIDs: c.ASPEquipmentIDs(),
 	}
 }
 
@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 
 	"github.com/cetteup/gasp/cmd/gasp/internal/handler/internal/dto"
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
 	"github.com/cetteup/gasp/internal/domain/player"
 	"github.com/cetteup/gasp/internal/util"
 	"github.com/cetteup/gasp/pkg/asp"
@@ -29,12 +29,8 @@ func (h *Handler) HandleGET(c echo.Context) error {
 		PID uint32 `query:"pid" validate:"required"`
 	}{}
 
-	if err := c.Bind(&params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("failed to bind request parameters: %w", err))
-	}
-
-	if err := validator.New().StructCtx(c.Request().Context(), params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("invalid parameters: %w", err))
+	if err := request.Bind(c, &params); err != nil {
+		return err
 	}
 
 	p, err := h.playerRepository.FindByID(c.Request().Context(), params.PID)
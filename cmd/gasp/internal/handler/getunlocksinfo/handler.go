@@ -6,9 +6,9 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
 	"github.com/cetteup/gasp/internal/domain/award"
 	"github.com/cetteup/gasp/internal/domain/player"
 	"github.com/cetteup/gasp/internal/domain/unlock"
@@ -21,17 +21,20 @@ type Handler struct {
 	playerRepository       player.Repository
 	awardRecordRepository  award.RecordRepository
 	unlockRecordRepository unlock.RecordRepository
+	policy                 unlock.Policy
 }
 
 func NewHandler(
 	playerRepository player.Repository,
 	awardRecordRepository award.RecordRepository,
 	unlockRecordRepository unlock.RecordRepository,
+	policy unlock.Policy,
 ) *Handler {
 	return &Handler{
 		playerRepository:       playerRepository,
 		awardRecordRepository:  awardRecordRepository,
 		unlockRecordRepository: unlockRecordRepository,
+		policy:                 policy,
 	}
 }
 
@@ -40,12 +43,8 @@ func (h *Handler) HandleGET(c echo.Context) error {
 		PID uint32 `query:"pid" validate:"required"`
 	}{}
 
-	if err := c.Bind(&params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("failed to bind request parameters: %w", err))
-	}
-
-	if err := validator.New().StructCtx(c.Request().Context(), params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("invalid parameters: %w", err))
+	if err := request.Bind(c, &params); err != nil {
+		return err
 	}
 
 	var p player.Player
@@ -84,12 +83,12 @@ func (h *Handler) HandleGET(c echo.Context) error {
 		return err
 	}
 
-	availablePoints := unlock.DetermineAvailablePoints(p, unlockRecords, awardRecords)
+	availablePoints := h.policy.DetermineAvailablePoints(p, unlockRecords, awardRecords)
 	resp := asp.NewOKResponse().
 		WriteHeader("pid", "nick", "asof").
 		WriteData(util.FormatUint(p.ID), p.Name, asp.Timestamp()).
 		WriteHeader("enlisted", "officer").
-		WriteData(util.FormatInt(availablePoints), "0").
+		WriteData(util.FormatInt(availablePoints.Enlisted), util.FormatInt(availablePoints.Officer)).
 		WriteHeader("id", "state")
 
 	for _, record := range unlockRecords {
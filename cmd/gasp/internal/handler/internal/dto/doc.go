@@ -1,3 +1,3 @@
-// Package domain is a collection of constants used in the ASP (endpoint) world.
-// Many constants also exist in the "overall"/backend-internal domain, but some values differ.
+// Package dto is a collection of helpers used in the ASP (endpoint) world.
+// IDs used by the ASP are defined by the active catalogue (see internal/catalogue), since they depend on the mod.
 package dto
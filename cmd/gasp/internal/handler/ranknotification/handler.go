@@ -5,20 +5,23 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/cache"
 	"github.com/cetteup/gasp/internal/domain/player"
 	"github.com/cetteup/gasp/pkg/asp"
 )
 
 type Handler struct {
 	playerRepository player.Repository
+	invalidator      cache.PlayerInvalidator
 }
 
-func NewHandler(playerRepository player.Repository) *Handler {
+func NewHandler(playerRepository player.Repository, invalidator cache.PlayerInvalidator) *Handler {
 	return &Handler{
 		playerRepository: playerRepository,
+		invalidator:      invalidator,
 	}
 }
 
@@ -27,12 +30,8 @@ func (h *Handler) HandleGET(c echo.Context) error {
 		PID uint32 `query:"pid" validate:"required"`
 	}{}
 
-	if err := c.Bind(&params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("failed to bind request parameters: %w", err))
-	}
-
-	if err := validator.New().StructCtx(c.Request().Context(), params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("invalid parameters: %w", err))
+	if err := request.Bind(c, &params); err != nil {
+		return err
 	}
 
 	p, err := h.playerRepository.FindByID(c.Request().Context(), params.PID)
@@ -49,6 +48,10 @@ func (h *Handler) HandleGET(c echo.Context) error {
 		if err2 != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to reset rank change flags: %w", err2))
 		}
+
+		if err2 = h.invalidator.InvalidatePlayer(c.Request().Context(), p.ID); err2 != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to invalidate cached player data: %w", err2))
+		}
 	}
 
 	return c.String(http.StatusOK, asp.NewOKResponse().Serialize())
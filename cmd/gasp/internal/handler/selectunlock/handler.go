@@ -8,10 +8,13 @@ import (
 	"slices"
 	"time"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
 
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/cache"
 	"github.com/cetteup/gasp/internal/domain/award"
+	"github.com/cetteup/gasp/internal/domain/event"
 	"github.com/cetteup/gasp/internal/domain/player"
 	"github.com/cetteup/gasp/internal/domain/unlock"
 	"github.com/cetteup/gasp/pkg/asp"
@@ -21,39 +24,57 @@ import (
 type Handler struct {
 	playerRepository       player.Repository
 	awardRecordRepository  award.RecordRepository
+	unlockRepository       unlock.Repository
 	unlockRecordRepository unlock.RecordRepository
+	policy                 unlock.Policy
+	invalidator            cache.PlayerInvalidator
+	publisher              event.Publisher
 }
 
 func NewHandler(
 	playerRepository player.Repository,
 	awardRecordRepository award.RecordRepository,
+	unlockRepository unlock.Repository,
 	unlockRecordRepository unlock.RecordRepository,
+	policy unlock.Policy,
+	invalidator cache.PlayerInvalidator,
+	publisher event.Publisher,
 ) *Handler {
 	return &Handler{
 		playerRepository:       playerRepository,
 		awardRecordRepository:  awardRecordRepository,
+		unlockRepository:       unlockRepository,
 		unlockRecordRepository: unlockRecordRepository,
+		policy:                 policy,
+		invalidator:            invalidator,
+		publisher:              publisher,
 	}
 }
 
 func (h *Handler) HandlePOST(c echo.Context) error {
 	params := struct {
 		PID      uint32 `form:"pid" validate:"required"`
-		UnlockID uint16 `form:"id" validate:"required,oneof=11 22 33 44 55 66 77 88 99 111 222 333 444 555"`
+		UnlockID uint16 `form:"id" validate:"required"`
 	}{}
 
-	if err := c.Bind(&params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("failed to bind request parameters: %w", err))
-	}
-
-	if err := validator.New().StructCtx(c.Request().Context(), params); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("invalid parameters: %w", err))
+	if err := request.Bind(c, &params); err != nil {
+		return err
 	}
 
+	var catalogue *unlock.Catalogue
 	var p player.Player
 	var unlockRecords []unlock.Record
 	var awardRecords []award.Record
 	var runner task.AsyncRunner
+	runner.Append(func(ctx context.Context) error {
+		// Load the catalogue for every request, so changes to the unlocks are picked up without a restart
+		unlocks, err2 := h.unlockRepository.FindAll(ctx)
+		if err2 != nil {
+			return fmt.Errorf("failed to find unlocks: %w", err2)
+		}
+		catalogue = unlock.NewCatalogue(unlocks)
+		return nil
+	})
 	runner.Append(func(ctx context.Context) error {
 		var err2 error
 		p, err2 = h.playerRepository.FindByID(ctx, params.PID)
@@ -86,6 +107,16 @@ func (h *Handler) HandlePOST(c echo.Context) error {
 		return err
 	}
 
+	if _, err := catalogue.Get(params.UnlockID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("unknown unlock: %d", params.UnlockID))
+	}
+
+	// Unlocks outside the policy's pools cannot be selected, even if they are part of the catalogue
+	kind, ok := h.policy.Pool(params.UnlockID)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("unlock not selectable: %d", params.UnlockID))
+	}
+
 	// Ensure selected unlock has not yet been unlocked
 	// Could also consider this a noop, but the endpoint is POST not PUT, not being idempotent is fine
 	if slices.ContainsFunc(unlockRecords, func(record unlock.Record) bool {
@@ -95,8 +126,13 @@ func (h *Handler) HandlePOST(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnprocessableEntity).SetInternal(errors.New("unlock already unlocked"))
 	}
 
-	// Ensure players has points available
-	if unlock.DetermineAvailablePoints(p, unlockRecords, awardRecords) < 1 {
+	// Ensure the unlocks required by the selected unlock have already been unlocked
+	if err := catalogue.CheckRequirements(params.UnlockID, unlockRecords); err != nil {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity).SetInternal(err)
+	}
+
+	// Ensure players has points available in the unlock's pool
+	if h.policy.DetermineAvailablePoints(p, unlockRecords, awardRecords).Get(kind) < 1 {
 		return echo.NewHTTPError(http.StatusUnprocessableEntity).SetInternal(errors.New("no unlock points available"))
 	}
 
@@ -117,5 +153,29 @@ func (h *Handler) HandlePOST(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to insert unlock record: %w", err))
 	}
 
+	if err = h.invalidator.InvalidatePlayer(c.Request().Context(), p.ID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to invalidate cached player data: %w", err))
+	}
+
+	// Unlock has been recorded at this point, so failing to publish should not fail the request
+	err = h.publisher.Publish(c.Request().Context(), event.Event{
+		Type: event.TypeUnlockSelected,
+		Player: event.PlayerRef{
+			ID:   p.ID,
+			Name: p.Name,
+		},
+		Timestamp: record.Timestamp,
+		Unlock: &event.UnlockChange{
+			ID: params.UnlockID,
+		},
+	})
+	if err != nil {
+		log.Ctx(c.Request().Context()).Error().
+			Err(err).
+			Uint32("pid", p.ID).
+			Uint16("unlock", params.UnlockID).
+			Msg("Failed to publish unlock selected event")
+	}
+
 	return c.String(http.StatusOK, asp.NewOKResponse().Serialize())
 }
@@ -0,0 +1,109 @@
+package eventwatch
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cetteup/gasp/internal/domain/event"
+	"github.com/cetteup/gasp/internal/domain/leaderboard"
+)
+
+const (
+	topSize = 10
+)
+
+// Job Publishes rank changes, awards, bans and top 10 changes, which are not written by gasp itself and thus need to
+// be detected by polling. Events caused by gasp's own write paths (rounds, unlocks) are published by the handlers
+// writing the data.
+type Job struct {
+	repository            event.WatchRepository
+	leaderboardRepository leaderboard.Repository
+	publisher             event.Publisher
+
+	// top Player ids of the last seen top 10, nil until the first run (only accessed by runs, which never overlap)
+	top []uint32
+}
+
+func NewJob(repository event.WatchRepository, leaderboardRepository leaderboard.Repository, publisher event.Publisher) *Job {
+	return &Job{
+		repository:            repository,
+		leaderboardRepository: leaderboardRepository,
+		publisher:             publisher,
+	}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	// Will overflow on 7 February 2106 at 06:28:15 UTC
+	timestamp := uint32(time.Now().UTC().Unix())
+	events, err := j.repository.FindUnseen(ctx, timestamp)
+	if err != nil {
+		return fmt.Errorf("failed to find unseen events: %w", err)
+	}
+
+	change, top, err := j.findTopChange(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find top %d change: %w", topSize, err)
+	}
+
+	if change != nil {
+		events = append(events, event.Event{
+			Type:        event.TypeLeaderboardChanged,
+			Timestamp:   timestamp,
+			Leaderboard: change,
+		})
+	}
+
+	if len(events) == 0 {
+		j.top = top
+		return nil
+	}
+
+	// Events are only marked as seen once published, so a failure results in them being published again next run
+	if err = j.publisher.Publish(ctx, events...); err != nil {
+		return fmt.Errorf("failed to publish events: %w", err)
+	}
+	j.top = top
+
+	if err = j.repository.MarkSeen(ctx, events); err != nil {
+		return fmt.Errorf("failed to mark events as seen: %w", err)
+	}
+
+	return nil
+}
+
+// findTopChange Returns the current top 10 of the overall score leaderboard if it changed since the last run
+// (ignoring changes in score), along with the ids of its players. No change is reported by the first run.
+func (j *Job) findTopChange(ctx context.Context) (*event.LeaderboardChange, []uint32, error) {
+	entries, _, err := j.leaderboardRepository.FindTopPlayersByScore(
+		ctx,
+		leaderboard.ScoreTypeOverall,
+		leaderboard.NewPositionFilter(0, topSize),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	top := make([]uint32, 0, len(entries))
+	change := &event.LeaderboardChange{
+		Entries: make([]event.LeaderboardEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		top = append(top, entry.Data.ID)
+		change.Entries = append(change.Entries, event.LeaderboardEntry{
+			Position: entry.Position,
+			Player: event.PlayerRef{
+				ID:   entry.Data.ID,
+				Name: entry.Data.Name,
+			},
+			Score: entry.Data.Score,
+		})
+	}
+
+	if j.top == nil || slices.Equal(j.top, top) {
+		return nil, top, nil
+	}
+
+	return change, top, nil
+}
@@ -0,0 +1,34 @@
+package risingstar
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cetteup/gasp/internal/domain/leaderboard"
+)
+
+// Job Computes rising stars from the score players gained within window
+type Job struct {
+	repository leaderboard.RisingStarRepository
+	window     time.Duration
+}
+
+func NewJob(repository leaderboard.RisingStarRepository, window time.Duration) (*Job, error) {
+	// Window reaching into the future would include every round ever played, rather than none
+	if window <= 0 {
+		return nil, errors.New("window must be positive")
+	}
+
+	return &Job{
+		repository: repository,
+		window:     window,
+	}, nil
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	now := time.Now().UTC()
+	since := now.Add(-j.window)
+	// Will overflow on 7 February 2106 at 06:28:15 UTC
+	return j.repository.Rebuild(ctx, uint32(since.Unix()), uint32(now.Unix()))
+}
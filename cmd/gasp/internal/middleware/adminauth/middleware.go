@@ -0,0 +1,41 @@
+package adminauth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderKey = "X-Admin-Key"
+)
+
+// New Returns a middleware which only lets requests presenting one of the admin keys pass. Admin access is disabled
+// (any request is rejected) if no keys are given.
+func New(keys []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if len(keys) == 0 {
+				return echo.NewHTTPError(http.StatusForbidden).SetInternal(errors.New("admin access is disabled"))
+			}
+
+			key := c.Request().Header.Get(HeaderKey)
+			if key == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized).SetInternal(errors.New("missing admin key"))
+			}
+
+			// Compare against every key, so the time taken does not reveal which (if any) key matched
+			var valid int
+			for _, k := range keys {
+				valid |= subtle.ConstantTimeCompare([]byte(key), []byte(k))
+			}
+			if valid != 1 {
+				return echo.NewHTTPError(http.StatusUnauthorized).SetInternal(errors.New("invalid admin key"))
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"errors"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/middleware/serverauth"
+	"github.com/cetteup/gasp/internal/domain/server"
+)
+
+type Limit struct {
+	// Rate Number of requests per second a single client may send (sustained)
+	Rate float64
+	// Burst Number of requests a single client may send at once
+	Burst int
+	// ExpiresIn Duration after which an idle client's limiter is removed
+	ExpiresIn time.Duration
+}
+
+// Factory Creates rate limiting middlewares, each with a separate per-client store (one per endpoint)
+type Factory struct {
+	defaultLimit Limit
+	limits       map[string]Limit
+	allowlist    []netip.Prefix
+	resolver     *serverauth.Resolver
+
+	mu sync.Mutex
+	// middlewares Middlewares created so far, keyed by endpoint
+	middlewares map[string]echo.MiddlewareFunc
+}
+
+// NewFactory Returns a new factory. If resolver is not nil, requests from registered servers are never limited.
+func NewFactory(defaultLimit Limit, limits map[string]Limit, allowlist []netip.Prefix, resolver *serverauth.Resolver) *Factory {
+	return &Factory{
+		defaultLimit: defaultLimit,
+		limits:       limits,
+		allowlist:    allowlist,
+		resolver:     resolver,
+		middlewares:  make(map[string]echo.MiddlewareFunc),
+	}
+}
+
+// For Returns a middleware limiting requests to the given endpoint, using the endpoint specific limit if configured.
+// Routes sharing an endpoint name share the middleware (and thus the limit), e.g. all routes of the JSON API.
+func (f *Factory) For(endpoint string) echo.MiddlewareFunc {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if m, ok := f.middlewares[endpoint]; ok {
+		return m
+	}
+
+	limit, ok := f.limits[endpoint]
+	if !ok {
+		limit = f.defaultLimit
+	}
+
+	m := middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Skipper: f.skip,
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+			Rate:      rate.Limit(limit.Rate),
+			Burst:     limit.Burst,
+			ExpiresIn: limit.ExpiresIn,
+		}),
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			// Client address is determined by the echo instance's IP extractor, which must only trust forwarded
+			// addresses sent by trusted proxies (else clients could evade the limit by sending arbitrary addresses)
+			return c.RealIP(), nil
+		},
+		// Errors are left to the error handler, which always responds with 200/OK and an ASP error
+		ErrorHandler: func(c echo.Context, err error) error {
+			return echo.NewHTTPError(http.StatusForbidden).SetInternal(err)
+		},
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			return echo.NewHTTPError(http.StatusTooManyRequests).SetInternal(err)
+		},
+	})
+	f.middlewares[endpoint] = m
+
+	return m
+}
+
+func (f *Factory) skip(c echo.Context) bool {
+	addr, err := netip.ParseAddr(c.RealIP())
+	if err != nil {
+		// Let the limiter deal with (i.e. limit) clients with unparsable addresses
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range f.allowlist {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	if f.resolver == nil {
+		return false
+	}
+
+	_, err = f.resolver.Resolve(c.Request().Context(), addr)
+	if err != nil {
+		if !errors.Is(err, server.ErrServerNotFound) {
+			log.Ctx(c.Request().Context()).Warn().
+				Err(err).
+				Str("remote", addr.String()).
+				Msg("Failed to resolve server for rate limiting")
+		}
+		return false
+	}
+
+	return true
+}
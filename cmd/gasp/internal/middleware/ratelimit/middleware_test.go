@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/middleware/serverauth"
+	"github.com/cetteup/gasp/internal/domain/server"
+)
+
+type serverRepository struct {
+	servers []server.Server
+	err     error
+}
+
+func (r serverRepository) FindAll(_ context.Context) ([]server.Server, error) {
+	return r.servers, r.err
+}
+
+func newContext(e *echo.Echo, remoteAddr string) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	return e.NewContext(req, httptest.NewRecorder())
+}
+
+func TestFactory_skip(t *testing.T) {
+	registered := serverauth.NewResolver(serverRepository{
+		servers: []server.Server{
+			{ID: 1, AuthorizedAddresses: []netip.Prefix{netip.MustParsePrefix("203.0.113.10/32")}},
+		},
+	}, time.Minute)
+	failing := serverauth.NewResolver(serverRepository{err: errors.New("connection refused")}, time.Minute)
+	allowlist := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		resolver   *serverauth.Resolver
+		want       bool
+	}{
+		{name: "allowlisted", remoteAddr: "10.1.2.3:1234", want: true},
+		{name: "allowlisted mapped IPv4", remoteAddr: "[::ffff:10.1.2.3]:1234", want: true},
+		{name: "not allowlisted", remoteAddr: "198.51.100.1:1234", want: false},
+		{name: "unparsable address", remoteAddr: "invalid", want: false},
+		{name: "registered server", remoteAddr: "203.0.113.10:1234", resolver: registered, want: true},
+		{name: "registered server without resolver", remoteAddr: "203.0.113.10:1234", want: false},
+		{name: "unknown server", remoteAddr: "203.0.113.11:1234", resolver: registered, want: false},
+		{name: "failing resolver", remoteAddr: "203.0.113.10:1234", resolver: failing, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.IPExtractor = echo.ExtractIPDirect()
+			f := NewFactory(Limit{Rate: 1, Burst: 1}, nil, allowlist, tt.resolver)
+
+			if got := f.skip(newContext(e, tt.remoteAddr)); got != tt.want {
+				t.Errorf("expected skip to be %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFactory_For(t *testing.T) {
+	f := NewFactory(Limit{Rate: 0.001, Burst: 1, ExpiresIn: time.Minute}, nil, nil, nil)
+
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	handler := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+	e.GET("/a", handler, f.For("api"))
+	e.GET("/b", handler, f.For("api"))
+	e.GET("/c", handler, f.For("other"))
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/a", want: http.StatusOK},
+		// Shares the limit with /a
+		{path: "/b", want: http.StatusTooManyRequests},
+		// Separate limit
+		{path: "/c", want: http.StatusOK},
+		{path: "/c", want: http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.RemoteAddr = "198.51.100.1:1234"
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("GET %s: expected status %d, got %d", tt.path, tt.want, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package seasonscope
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/domain/season"
+)
+
+const (
+	QueryParamSeason = "season"
+)
+
+// New Returns a middleware which scopes requests to an archived season, selected either via the "season" query
+// parameter or the host the request was sent to (with the parameter taking precedence). Requests for the running
+// season are not scoped, since its stats are the live stats.
+func New(repository season.Repository, hosts map[string]uint32) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id, ok, err := resolve(c, hosts)
+			if err != nil {
+				return request.NewInvalidParametersError(err)
+			}
+			if !ok {
+				return next(c)
+			}
+
+			s, err := repository.FindByID(c.Request().Context(), id)
+			if err != nil {
+				if errors.Is(err, season.ErrSeasonNotFound) {
+					return echo.NewHTTPError(http.StatusNotFound).SetInternal(fmt.Errorf("unknown season: %d", id))
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to find season: %w", err))
+			}
+
+			if s.IsArchived() {
+				c.SetRequest(c.Request().WithContext(season.NewContext(c.Request().Context(), s.ID)))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func resolve(c echo.Context, hosts map[string]uint32) (uint32, bool, error) {
+	if param := c.QueryParam(QueryParamSeason); param != "" {
+		id, err := strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid season: %s", param)
+		}
+		return uint32(id), true, nil
+	}
+
+	host := c.Request().Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	id, ok := hosts[host]
+	return id, ok, nil
+}
@@ -0,0 +1,56 @@
+package serverauth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cetteup/gasp/internal/domain/server"
+)
+
+const (
+	HeaderKey = "X-Server-Key"
+
+	contextKey = "server"
+)
+
+// New Returns a middleware which only lets requests from registered servers pass. Errors are returned as
+// regular echo.HTTPError, leaving it to the error handler to wrap them in an ASP error response.
+func New(resolver *Resolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// Relies on the echo instance's IP extractor to only trust forwarded addresses sent by trusted proxies
+			addr, err := netip.ParseAddr(c.RealIP())
+			if err != nil {
+				return echo.NewHTTPError(http.StatusForbidden).SetInternal(fmt.Errorf("failed to parse remote address: %w", err))
+			}
+
+			s, err := resolver.Resolve(c.Request().Context(), addr)
+			if err != nil {
+				if errors.Is(err, server.ErrServerNotFound) {
+					return echo.NewHTTPError(http.StatusForbidden).SetInternal(fmt.Errorf("unknown server: %s", addr))
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("failed to resolve server: %w", err))
+			}
+
+			// Key is optional, servers without one are authorized by address only
+			if s.Key != "" && subtle.ConstantTimeCompare([]byte(c.Request().Header.Get(HeaderKey)), []byte(s.Key)) != 1 {
+				return echo.NewHTTPError(http.StatusForbidden).SetInternal(fmt.Errorf("invalid key for server: %d", s.ID))
+			}
+
+			c.Set(contextKey, s)
+
+			return next(c)
+		}
+	}
+}
+
+// FromContext Returns the server authorized by the middleware (if any)
+func FromContext(c echo.Context) (server.Server, bool) {
+	s, ok := c.Get(contextKey).(server.Server)
+	return s, ok
+}
@@ -0,0 +1,62 @@
+package serverauth
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/cetteup/gasp/internal/domain/server"
+)
+
+// Resolver Resolves remote addresses to registered servers, keeping the (small) registry in memory for ttl
+type Resolver struct {
+	repository server.Repository
+	ttl        time.Duration
+
+	mu      sync.Mutex
+	servers []server.Server
+	expires time.Time
+}
+
+func NewResolver(repository server.Repository, ttl time.Duration) *Resolver {
+	return &Resolver{
+		repository: repository,
+		ttl:        ttl,
+	}
+}
+
+func (r *Resolver) Resolve(ctx context.Context, addr netip.Addr) (server.Server, error) {
+	servers, err := r.load(ctx)
+	if err != nil {
+		return server.Server{}, err
+	}
+
+	for _, s := range servers {
+		if s.IsAuthorizedAddress(addr) {
+			return s, nil
+		}
+	}
+
+	return server.Server{}, server.ErrServerNotFound
+}
+
+func (r *Resolver) load(ctx context.Context) ([]server.Server, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.servers != nil && time.Now().Before(r.expires) {
+		return r.servers, nil
+	}
+
+	servers, err := r.repository.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find servers: %w", err)
+	}
+
+	r.servers = servers
+	r.expires = time.Now().Add(r.ttl)
+
+	return r.servers, nil
+}
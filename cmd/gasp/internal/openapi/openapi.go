@@ -0,0 +1,117 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml
+var spec []byte
+
+var methods = []string{
+	http.MethodGet,
+	http.MethodPut,
+	http.MethodPost,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodHead,
+	http.MethodPatch,
+	http.MethodTrace,
+}
+
+var paramPattern = regexp.MustCompile(`:([^/]+)`)
+
+type document struct {
+	// Paths Path items are decoded lazily, since they contain fields other than operations (e.g. parameters)
+	Paths map[string]map[string]yaml.Node `yaml:"paths"`
+}
+
+type operation struct {
+	// Optional Operation is only registered if the corresponding feature is enabled
+	Optional bool `yaml:"x-optional"`
+}
+
+type Handler struct {
+	json []byte
+}
+
+// NewHandler Returns a handler serving the embedded specification, which is written as YAML (being far easier to
+// maintain) but served as JSON
+func NewHandler() (*Handler, error) {
+	var raw map[string]any
+	if err := yaml.Unmarshal(spec, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi specification: %w", err)
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode openapi specification: %w", err)
+	}
+
+	return &Handler{
+		json: encoded,
+	}, nil
+}
+
+func (h *Handler) HandleGET(c echo.Context) error {
+	return c.JSONBlob(http.StatusOK, h.json)
+}
+
+// CheckRoutes Verifies the specification documents every route and every documented operation is registered
+// (unless marked as optional), so neither can silently fall out of sync with the other
+func CheckRoutes(routes []*echo.Route) error {
+	var doc document
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return fmt.Errorf("failed to parse openapi specification: %w", err)
+	}
+
+	registered := make(map[string]bool, len(routes))
+	var errs []error
+	for _, r := range routes {
+		// Echo registers internal routes using non-standard methods (e.g. for not found handlers)
+		if !slices.Contains(methods, r.Method) {
+			continue
+		}
+
+		// Convert echo path parameters (":pid") to OpenAPI ones ("{pid}")
+		path := paramPattern.ReplaceAllString(r.Path, "{$1}")
+		method := strings.ToLower(r.Method)
+		registered[method+" "+path] = true
+
+		if _, ok := doc.Paths[path][method]; !ok {
+			errs = append(errs, fmt.Errorf("route not documented: %s %s", r.Method, path))
+		}
+	}
+
+	// Iterate in order to report missing routes in a stable order
+	for _, path := range slices.Sorted(maps.Keys(doc.Paths)) {
+		item := doc.Paths[path]
+		for _, method := range slices.Sorted(maps.Keys(item)) {
+			node := item[method]
+			if !slices.Contains(methods, strings.ToUpper(method)) {
+				// Path level fields such as parameters or summary
+				continue
+			}
+
+			var op operation
+			if err := node.Decode(&op); err != nil {
+				return fmt.Errorf("failed to parse openapi operation %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			if !op.Optional && !registered[method+" "+path] {
+				errs = append(errs, fmt.Errorf("documented route not registered: %s %s", strings.ToUpper(method), path))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+package realm
+
+import (
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Router Dispatches requests to the realm matching the request's Host header and path prefix. Realms bound to a host
+// take precedence over realms served on any host, longer prefixes take precedence over shorter ones.
+type Router struct {
+	realms []entry
+}
+
+type entry struct {
+	hosts   []string
+	prefix  string
+	handler http.Handler
+}
+
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Add Adds a realm, stripping the prefix (if any) from the path before passing requests to the handler
+func (r *Router) Add(hosts []string, prefix string, handler http.Handler) {
+	normalized := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		normalized = append(normalized, strings.ToLower(host))
+	}
+
+	if prefix != "" {
+		handler = http.StripPrefix(prefix, handler)
+	}
+
+	r.realms = append(r.realms, entry{
+		hosts:   normalized,
+		prefix:  prefix,
+		handler: handler,
+	})
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	e, ok := r.match(req.Host, req.URL.Path)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	e.handler.ServeHTTP(w, req)
+}
+
+func (r *Router) match(host, path string) (entry, bool) {
+	// Host header may or may not contain a port
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+
+	var best entry
+	var found bool
+	for _, e := range r.realms {
+		if len(e.hosts) > 0 && !slices.Contains(e.hosts, host) {
+			continue
+		}
+		if e.prefix != "" && path != e.prefix && !strings.HasPrefix(path, e.prefix+"/") {
+			continue
+		}
+
+		if !found || e.outranks(best) {
+			best = e
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+func (e entry) outranks(other entry) bool {
+	if (len(e.hosts) > 0) != (len(other.hosts) > 0) {
+		return len(e.hosts) > 0
+	}
+	return len(e.prefix) > len(other.prefix)
+}
@@ -0,0 +1,50 @@
+// Package request binds and validates request parameters the same way for every handler, so invalid parameters are
+// reported consistently (as the ASP's syntax error for ASP endpoints)
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ErrInvalidParameters Wrapped by any error caused by invalid request parameters
+var ErrInvalidParameters = errors.New("invalid parameters")
+
+// ContextValidator Validator which validates as part of handling a request, implemented by Validator
+type ContextValidator interface {
+	ValidateCtx(ctx context.Context, i any) error
+}
+
+// Bind Binds the request parameters to params, which are then validated using the echo instance's validator (passing
+// along the request's context if the validator supports it)
+func Bind(c echo.Context, params any) error {
+	if err := c.Bind(params); err != nil {
+		return NewInvalidParametersError(fmt.Errorf("failed to bind request parameters: %w", err))
+	}
+
+	if err := validate(c, params); err != nil {
+		if errors.Is(err, echo.ErrValidatorNotRegistered) {
+			return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+		}
+		return NewInvalidParametersError(err)
+	}
+
+	return nil
+}
+
+func validate(c echo.Context, params any) error {
+	if v, ok := c.Echo().Validator.(ContextValidator); ok {
+		return v.ValidateCtx(c.Request().Context(), params)
+	}
+	return c.Validate(params)
+}
+
+// NewInvalidParametersError Returns a bad request error for parameters found to be invalid (e.g. by checks which
+// cannot be expressed as validation tags)
+func NewInvalidParametersError(err error) *echo.HTTPError {
+	return echo.NewHTTPError(http.StatusBadRequest).SetInternal(fmt.Errorf("%w: %w", ErrInvalidParameters, err))
+}
@@ -0,0 +1,72 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/cetteup/gasp/internal/catalogue"
+)
+
+// Validator Validates request parameters, implementing echo.Validator. Validate caches struct metadata, so a single
+// instance should be shared by all handlers rather than creating one per request.
+type Validator struct {
+	validate *validator.Validate
+}
+
+// NewValidator Returns a validator supporting tags for ids of the given catalogue, in addition to the built-in tags:
+//   - army: army id
+//   - field: field (map) id as used in the database
+//   - aspfield: field (map) id as used by the ASP
+//   - kit: kit id
+//   - vehicle: vehicle id
+//   - weapon: weapon id as used in the database
+//   - aspweapon: weapon id as used by the ASP
+func NewValidator(c *catalogue.Catalogue) (*Validator, error) {
+	validate := validator.New()
+
+	validations := map[string]validator.Func{
+		"army":      isKnownID(c.ArmyIDs()),
+		"field":     isKnownID(c.FieldIDs()),
+		"aspfield":  isKnownID(c.ASPFieldIDs()),
+		"kit":       isKnownID(c.KitIDs()),
+		"vehicle":   isKnownID(c.VehicleIDs()),
+		"weapon":    isKnownID(c.WeaponIDs()),
+		"aspweapon": isKnownID(c.ASPWeaponIDs()),
+	}
+	for tag, fn := range validations {
+		if err := validate.RegisterValidation(tag, fn); err != nil {
+			return nil, fmt.Errorf("failed to register %s validation: %w", tag, err)
+		}
+	}
+
+	return &Validator{
+		validate: validate,
+	}, nil
+}
+
+// Validate Validates without a request context, prefer ValidateCtx where one is available
+func (v *Validator) Validate(i any) error {
+	return v.ValidateCtx(context.Background(), i)
+}
+
+// ValidateCtx Validates as part of handling the request the context belongs to
+func (v *Validator) ValidateCtx(ctx context.Context, i any) error {
+	return v.validate.StructCtx(ctx, i)
+}
+
+func isKnownID[T uint8 | uint16](known []T) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		// Ids are unsigned, so any other type of value cannot be a known id
+		if !fl.Field().CanUint() {
+			return false
+		}
+
+		id := fl.Field().Uint()
+		return slices.ContainsFunc(known, func(k T) bool {
+			return uint64(k) == id
+		})
+	}
+}
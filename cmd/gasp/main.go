@@ -1,41 +1,24 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
-	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"github.com/cetteup/gasp/cmd/gasp/internal/certificate"
 	"github.com/cetteup/gasp/cmd/gasp/internal/config"
-	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getawardsinfo"
-	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getbackendinfo"
-	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getleaderboard"
-	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getplayerinfo"
-	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getrankinfo"
-	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getunlocksinfo"
-	"github.com/cetteup/gasp/cmd/gasp/internal/handler/ranknotification"
-	"github.com/cetteup/gasp/cmd/gasp/internal/handler/searchforplayers"
-	"github.com/cetteup/gasp/cmd/gasp/internal/handler/selectunlock"
-	"github.com/cetteup/gasp/cmd/gasp/internal/handler/verifyplayer"
 	"github.com/cetteup/gasp/cmd/gasp/internal/options"
-	armysql "github.com/cetteup/gasp/internal/domain/army/sql"
-	awardsql "github.com/cetteup/gasp/internal/domain/award/sql"
-	fieldsql "github.com/cetteup/gasp/internal/domain/field/sql"
-	killsql "github.com/cetteup/gasp/internal/domain/kill/sql"
-	kitsql "github.com/cetteup/gasp/internal/domain/kit/sql"
-	leaderboardsql "github.com/cetteup/gasp/internal/domain/leaderboard/sql"
-	playersql "github.com/cetteup/gasp/internal/domain/player/sql"
-	unlocksql "github.com/cetteup/gasp/internal/domain/unlock/sql"
-	vehiclesql "github.com/cetteup/gasp/internal/domain/vehicle/sql"
-	weaponsql "github.com/cetteup/gasp/internal/domain/weapon/sql"
-	"github.com/cetteup/gasp/internal/sqlutil"
-	"github.com/cetteup/gasp/pkg/asp"
+	"github.com/cetteup/gasp/cmd/gasp/internal/realm"
 )
 
 var (
@@ -65,6 +48,9 @@ func main() {
 		zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	}
 
+	// Anything logging via a context without a (realm) logger falls back to the global logger
+	zerolog.DefaultContextLogger = &log.Logger
+
 	cfg, err := config.LoadConfig(opts.ConfigPath)
 	if err != nil {
 		log.Fatal().
@@ -73,130 +59,96 @@ func main() {
 			Msg("Failed to read config file")
 	}
 
-	db := sqlutil.Connect(
-		cfg.Database.Host,
-		cfg.Database.DatabaseName,
-		cfg.Database.Username,
-		cfg.Database.Password,
-	)
-	defer func() {
-		err2 := db.Close()
+	// Realms share a single listener, requests are routed to them by host and path prefix
+	router := realm.NewRouter()
+	realms := make([]*hostedRealm, 0, len(cfg.Realms))
+	for _, rc := range cfg.Realms {
+		r, err2 := buildRealm(rc, cfg.HTTP)
 		if err2 != nil {
-			log.Error().
+			log.Fatal().
 				Err(err2).
-				Msg("Failed to close database connection")
+				Str("realm", rc.Name).
+				Msg("Failed to set up realm")
 		}
-	}()
+		defer r.Close()
+
+		router.Add(rc.Hosts, rc.Prefix, r.handler)
+		realms = append(realms, r)
+	}
 
-	playerRepository := playersql.NewRepository(db)
-	armyRecordRepository := armysql.NewRecordRepository(db)
-	awardRecordRepository := awardsql.NewRecordRepository(db)
-	fieldRecordRepository := fieldsql.NewRecordRepository(db)
-	killHistoryRecordRepository := killsql.NewHistoryRecordRepository(db)
-	kitRecordRepository := kitsql.NewRecordRepository(db)
-	leaderboardRepository := leaderboardsql.NewRepository(db)
-	vehicleRecordRepository := vehiclesql.NewRecordRepository(db)
-	weaponRecordRepository := weaponsql.NewRecordRepository(db)
-	unlockRepository := unlocksql.NewRepository(db)
-	unlockRecordRepository := unlocksql.NewRecordRepository(db)
-	gaih := getawardsinfo.NewHandler(awardRecordRepository)
-	gbih := getbackendinfo.NewHandler(unlockRepository)
-	glbh := getleaderboard.NewHandler(leaderboardRepository)
-	gpih := getplayerinfo.NewHandler(
-		playerRepository,
-		armyRecordRepository,
-		fieldRecordRepository,
-		killHistoryRecordRepository,
-		kitRecordRepository,
-		vehicleRecordRepository,
-		weaponRecordRepository,
-	)
-	grih := getrankinfo.NewHandler(playerRepository)
-	guih := getunlocksinfo.NewHandler(playerRepository, awardRecordRepository, unlockRecordRepository)
-	rnh := ranknotification.NewHandler(playerRepository)
-	sfph := searchforplayers.NewHandler(playerRepository)
-	suh := selectunlock.NewHandler(playerRepository, awardRecordRepository, unlockRecordRepository)
-	vph := verifyplayer.NewHandler(playerRepository)
-
-	e := echo.New()
-	e.HideBanner = true
-	e.HidePort = true
-	// Error handler is strongly modeled after the default one
-	e.HTTPErrorHandler = func(err error, c echo.Context) {
-		if c.Response().Committed {
-			return
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var wg sync.WaitGroup
+	for _, r := range realms {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Run(ctx)
+		}()
+	}
+
+	srv := &http.Server{
+		Addr:    opts.ListenAddr,
+		Handler: router,
+	}
+	if cfg.HTTP.TLS.CertFile != "" {
+		reloader, err2 := certificate.NewReloader(cfg.HTTP.TLS.CertFile, cfg.HTTP.TLS.KeyFile)
+		if err2 != nil {
+			log.Fatal().
+				Err(err2).
+				Str("cert", cfg.HTTP.TLS.CertFile).
+				Str("key", cfg.HTTP.TLS.KeyFile).
+				Msg("Failed to load TLS certificate")
 		}
 
-		code := http.StatusInternalServerError
-		message := http.StatusText(code)
-		var he *echo.HTTPError
-		if errors.As(err, &he) {
-			code = he.Code
-			message = http.StatusText(code)
+		srv.TLSConfig = &tls.Config{
+			GetCertificate: reloader.GetCertificate,
+			MinVersion:     tls.VersionTLS12,
 		}
 
-		// Send response
-		if c.Request().Method == http.MethodHead {
-			err = c.NoContent(code)
-		} else {
-			// Always return 200/OK to match original GameSpy behaviour.
-			// Note: Logs will contain the "underlying" status code, not 200.
-			err = c.String(http.StatusOK, asp.NewErrorResponseWithMessage(code, message).Serialize())
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reloader.Run(ctx, cfg.HTTP.TLS.Reload)
+		}()
+	}
+	srv.RegisterOnShutdown(func() {
+		for _, r := range realms {
+			r.Shutdown()
 		}
-		if err != nil {
+	})
+
+	go func() {
+		<-ctx.Done()
+		log.Info().Msg("Shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
 			log.Error().
 				Err(err).
-				Msg("Failed to send error response")
+				Msg("Failed to shut down server")
 		}
+	}()
 
+	log.Info().
+		Str("address", opts.ListenAddr).
+		Int("realms", len(realms)).
+		Bool("tls", srv.TLSConfig != nil).
+		Msg("Starting server")
+	if srv.TLSConfig != nil {
+		// Certificate is provided by the config's GetCertificate
+		err = srv.ListenAndServeTLS("", "")
+	} else {
+		err = srv.ListenAndServe()
 	}
-	e.Use(middleware.Recover())
-	e.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
-		Timeout: time.Second * 10,
-		ErrorMessage: asp.NewErrorResponseWithMessage(
-			http.StatusServiceUnavailable,
-			http.StatusText(http.StatusServiceUnavailable),
-		).Serialize(),
-	}))
-	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
-		LogError:     true,
-		LogRemoteIP:  true,
-		LogMethod:    true,
-		LogURI:       true,
-		LogStatus:    true,
-		LogLatency:   true,
-		LogUserAgent: true,
-		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
-			log.Info().
-				Err(v.Error).
-				Str("remote", v.RemoteIP).
-				Str("method", v.Method).
-				Str("URI", v.URI).
-				Int("status", v.Status).
-				Str("latency", v.Latency.Truncate(time.Millisecond).String()).
-				Str("agent", v.UserAgent).
-				Msg("request")
-
-			return nil
-		},
-	}))
-
-	g := e.Group("/ASP")
-	g.GET("/getawardsinfo.aspx", gaih.HandleGET)
-	g.GET("/getbackendinfo.aspx", gbih.HandleGET)
-	g.GET("/getleaderboard.aspx", glbh.HandleGET)
-	g.GET("/getplayerinfo.aspx", gpih.HandleGET)
-	g.GET("/getrankinfo.aspx", grih.HandleGET)
-	g.GET("/getunlocksinfo.aspx", guih.HandleGET)
-	g.GET("/ranknotification.aspx", rnh.HandleGET)
-	g.GET("/searchforplayers.aspx", sfph.HandleGET)
-	g.GET("/VerifyPlayer.aspx", vph.HandleGET)
-	g.POST("/selectunlock.aspx", suh.HandlePOST)
-
-	if err = e.Start(opts.ListenAddr); !errors.Is(err, http.ErrServerClosed) {
+	if !errors.Is(err, http.ErrServerClosed) {
 		log.Fatal().
 			Err(err).
 			Str("address", opts.ListenAddr).
 			Msg("Failed to start server")
 	}
+
+	// Wait for any running jobs to return
+	wg.Wait()
 }
@@ -0,0 +1,684 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/config"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/admin/listjobs"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/admin/manageclans"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/admin/revokeunlock"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/admin/setrespecs"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/admin/startseason"
+	apiclan "github.com/cetteup/gasp/cmd/gasp/internal/handler/api/clan"
+	apievents "github.com/cetteup/gasp/cmd/gasp/internal/handler/api/events"
+	apikillhistory "github.com/cetteup/gasp/cmd/gasp/internal/handler/api/killhistory"
+	apileaderboard "github.com/cetteup/gasp/cmd/gasp/internal/handler/api/leaderboard"
+	apiplayer "github.com/cetteup/gasp/cmd/gasp/internal/handler/api/player"
+	apiround "github.com/cetteup/gasp/cmd/gasp/internal/handler/api/round"
+	apiseason "github.com/cetteup/gasp/cmd/gasp/internal/handler/api/season"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getawardsinfo"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getbackendinfo"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getleaderboard"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getplayerinfo"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getrankinfo"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/getunlocksinfo"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/ranknotification"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/searchforplayers"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/selectunlock"
+	"github.com/cetteup/gasp/cmd/gasp/internal/handler/verifyplayer"
+	"github.com/cetteup/gasp/cmd/gasp/internal/job/eventwatch"
+	"github.com/cetteup/gasp/cmd/gasp/internal/job/risingstar"
+	"github.com/cetteup/gasp/cmd/gasp/internal/middleware/adminauth"
+	"github.com/cetteup/gasp/cmd/gasp/internal/middleware/ratelimit"
+	"github.com/cetteup/gasp/cmd/gasp/internal/middleware/seasonscope"
+	"github.com/cetteup/gasp/cmd/gasp/internal/middleware/serverauth"
+	"github.com/cetteup/gasp/cmd/gasp/internal/openapi"
+	"github.com/cetteup/gasp/cmd/gasp/internal/request"
+	"github.com/cetteup/gasp/internal/bus"
+	"github.com/cetteup/gasp/internal/cache"
+	"github.com/cetteup/gasp/internal/catalogue"
+	armysql "github.com/cetteup/gasp/internal/domain/army/sql"
+	awardsql "github.com/cetteup/gasp/internal/domain/award/sql"
+	clansql "github.com/cetteup/gasp/internal/domain/clan/sql"
+	"github.com/cetteup/gasp/internal/domain/event"
+	eventsql "github.com/cetteup/gasp/internal/domain/event/sql"
+	fieldsql "github.com/cetteup/gasp/internal/domain/field/sql"
+	jobsql "github.com/cetteup/gasp/internal/domain/job/sql"
+	killsql "github.com/cetteup/gasp/internal/domain/kill/sql"
+	kitsql "github.com/cetteup/gasp/internal/domain/kit/sql"
+	"github.com/cetteup/gasp/internal/domain/leaderboard"
+	leaderboardcached "github.com/cetteup/gasp/internal/domain/leaderboard/cached"
+	leaderboardsnapshot "github.com/cetteup/gasp/internal/domain/leaderboard/snapshot"
+	leaderboardsql "github.com/cetteup/gasp/internal/domain/leaderboard/sql"
+	playersql "github.com/cetteup/gasp/internal/domain/player/sql"
+	roundsql "github.com/cetteup/gasp/internal/domain/round/sql"
+	seasonsql "github.com/cetteup/gasp/internal/domain/season/sql"
+	"github.com/cetteup/gasp/internal/domain/server"
+	serversql "github.com/cetteup/gasp/internal/domain/server/sql"
+	"github.com/cetteup/gasp/internal/domain/unlock"
+	unlocksql "github.com/cetteup/gasp/internal/domain/unlock/sql"
+	vehiclesql "github.com/cetteup/gasp/internal/domain/vehicle/sql"
+	weaponsql "github.com/cetteup/gasp/internal/domain/weapon/sql"
+	"github.com/cetteup/gasp/internal/schedule"
+	"github.com/cetteup/gasp/internal/sqlutil"
+	"github.com/cetteup/gasp/internal/webhook"
+	"github.com/cetteup/gasp/pkg/asp"
+)
+
+// hostedRealm An independent community served by the process, with its own database, repositories, jobs and catalogue
+type hostedRealm struct {
+	name      string
+	logger    zerolog.Logger
+	handler   *echo.Echo
+	scheduler *schedule.Scheduler
+	closers   []func()
+	// workers Background tasks to run alongside the scheduler (e.g. replica lag checks)
+	workers []func(ctx context.Context)
+	// bus Feeds the event stream, nil if the stream is disabled
+	bus *bus.Bus
+}
+
+// Run Runs the realm's jobs and background tasks until the context is cancelled
+func (r *hostedRealm) Run(ctx context.Context) {
+	// Pass the realm's logger along, so logs are attributed to the realm
+	ctx = r.logger.WithContext(ctx)
+
+	var wg sync.WaitGroup
+	for _, w := range r.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w(ctx)
+		}()
+	}
+
+	r.scheduler.Run(ctx)
+	wg.Wait()
+}
+
+// Shutdown Ends any long-lived requests (event streams), which would otherwise keep the server from shutting down
+func (r *hostedRealm) Shutdown() {
+	if r.bus != nil {
+		r.bus.Close()
+	}
+}
+
+// Close Closes the realm's database connections
+func (r *hostedRealm) Close() {
+	for _, c := range r.closers {
+		c()
+	}
+}
+
+func buildRealm(cfg config.RealmConfig, httpCfg config.HTTPConfig) (*hostedRealm, error) {
+	r := &hostedRealm{
+		name:   cfg.Name,
+		logger: log.With().Str("realm", cfg.Name).Logger(),
+	}
+
+	// Catalogue of the mod's armies, fields, kits, vehicles and weapons (vanilla BF2 unless configured otherwise)
+	cat, err := catalogue.Load(cfg.Catalogue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load catalogue %q: %w", cfg.Catalogue, err)
+	}
+
+	db := sqlutil.Connect(
+		cfg.Database.Host,
+		cfg.Database.DatabaseName,
+		cfg.Database.Username,
+		cfg.Database.Password,
+	)
+	r.closers = append(r.closers, func() {
+		err2 := db.Close()
+		if err2 != nil {
+			r.logger.Error().
+				Err(err2).
+				Msg("Failed to close database connection")
+		}
+	})
+
+	// Stats are read from replicas (if any), since reads vastly outnumber writes
+	var live sqlutil.DB = db
+	if len(cfg.Database.Replicas) > 0 {
+		replicas := make([]sqlutil.Replica, 0, len(cfg.Database.Replicas))
+		for _, rc := range cfg.Database.Replicas {
+			replicas = append(replicas, sqlutil.Replica{
+				Name: rc.Host,
+				DB: sqlutil.Connect(
+					rc.Host,
+					cfg.Database.DatabaseName,
+					rc.Username,
+					rc.Password,
+				),
+			})
+		}
+
+		set := sqlutil.NewReplicaSet(db, replicas, cfg.Database.MaxLag)
+		r.closers = append(r.closers, func() {
+			err2 := set.Close()
+			if err2 != nil {
+				r.logger.Error().
+					Err(err2).
+					Msg("Failed to close replica database connections")
+			}
+		})
+		r.workers = append(r.workers, func(ctx context.Context) {
+			set.Run(ctx, cfg.Database.LagCheck)
+		})
+		live = set
+	}
+
+	// Stats are read via the season runner, which reads from a season's archive for requests scoped to said season
+	runner := seasonsql.NewRunner(live, cfg.Seasons.SchemaPrefix, func(schema string) *sql.DB {
+		return sqlutil.Connect(
+			cfg.Database.Host,
+			schema,
+			cfg.Database.Username,
+			cfg.Database.Password,
+		)
+	})
+	r.closers = append(r.closers, func() {
+		err2 := runner.Close()
+		if err2 != nil {
+			r.logger.Error().
+				Err(err2).
+				Msg("Failed to close season archive database connections")
+		}
+	})
+
+	playerRepository := playersql.NewRepository(runner)
+	armyRecordRepository := armysql.NewRecordRepository(runner)
+	awardRecordRepository := awardsql.NewRecordRepository(runner)
+	clanStatsRepository := clansql.NewStatsRepository(runner)
+	fieldRecordRepository := fieldsql.NewRecordRepository(runner)
+	killHistoryRecordRepository := killsql.NewHistoryRecordRepository(runner)
+	kitRecordRepository := kitsql.NewRecordRepository(runner)
+	roundHistoryRepository := roundsql.NewHistoryRepository(runner)
+	var leaderboardRepository leaderboard.Repository = leaderboardsql.NewRepository(runner)
+	vehicleRecordRepository := vehiclesql.NewRecordRepository(runner)
+	weaponRecordRepository := weaponsql.NewRecordRepository(runner)
+	unlockRepository := unlocksql.NewRepository(db)
+	unlockRecordRepository := unlocksql.NewRecordRepository(db)
+	respecRepository := unlocksql.NewRespecRepository(db)
+	clanRepository := clansql.NewRepository(db)
+	serverRepository := serversql.NewRepository(db)
+	seasonRepository := seasonsql.NewRepository(db)
+	seasonArchiver := seasonsql.NewArchiver(db, cfg.Seasons.SchemaPrefix)
+
+	jobRepository := jobsql.NewRepository(db)
+
+	// Anything caching stats needs to be invalidated when stats are reset, snapshots first so caches are not refilled
+	// from stale snapshots
+	var statsInvalidators cache.StatsInvalidators
+	r.scheduler = schedule.NewScheduler(jobRepository)
+	if cfg.Jobs.Snapshot.Enabled {
+		snapshotRepository := leaderboardsnapshot.NewRepository(leaderboardRepository, cat)
+		leaderboardRepository = snapshotRepository
+		statsInvalidators = append(statsInvalidators, snapshotRepository)
+		err = r.scheduler.Add(schedule.Job{
+			Name: "snapshot",
+			Spec: cfg.Jobs.Snapshot.Schedule,
+			Task: snapshotRepository.Refresh,
+			// Snapshots only live in memory, so they always need to be taken on start
+			RunOnStart: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add snapshot job: %w", err)
+		}
+	}
+	if cfg.Jobs.RisingStar.Enabled {
+		job, err2 := risingstar.NewJob(leaderboardsql.NewRisingStarRepository(db), cfg.Jobs.RisingStar.Window)
+		if err2 != nil {
+			return nil, fmt.Errorf("failed to set up risingstar job: %w", err2)
+		}
+		err = r.scheduler.Add(schedule.Job{
+			Name: "risingstar",
+			Spec: cfg.Jobs.RisingStar.Schedule,
+			Task: job.Run,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add risingstar job: %w", err)
+		}
+	}
+
+	webhooks, err := buildWebhooks(cfg.Webhooks.Endpoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up webhooks: %w", err)
+	}
+	outboxRepository := eventsql.NewOutboxRepository(db)
+	// Webhook publisher does not queue anything if no webhooks are configured
+	publisher := event.Publishers{webhook.NewPublisher(outboxRepository, webhooks)}
+	if cfg.Stream.Enabled {
+		r.bus = bus.New()
+		publisher = append(publisher, r.bus)
+	}
+	// Only watch for events if anyone is listening
+	if len(webhooks) > 0 || cfg.Stream.Enabled {
+		job := eventwatch.NewJob(eventsql.NewWatchRepository(db), leaderboardsql.NewRepository(db), publisher)
+		err = r.scheduler.Add(schedule.Job{
+			Name: "eventwatch",
+			Spec: cfg.Webhooks.Watch,
+			Task: job.Run,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add eventwatch job: %w", err)
+		}
+	}
+	if len(webhooks) > 0 {
+		dispatcher := webhook.NewDispatcher(outboxRepository, webhooks, webhook.DispatcherOptions{
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			Backoff:     cfg.Webhooks.Backoff,
+			MaxBackoff:  cfg.Webhooks.MaxBackoff,
+			Timeout:     cfg.Webhooks.Timeout,
+		})
+		err = r.scheduler.Add(schedule.Job{
+			Name: "webhooks",
+			Spec: cfg.Webhooks.Deliver,
+			Task: dispatcher.Run,
+			// Deliver anything left in the outbox right away
+			RunOnStart: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add webhooks job: %w", err)
+		}
+	}
+
+	// Any cache holding player data needs to be invalidated when said data is written
+	var invalidators cache.Invalidators
+	if cfg.Cache.Leaderboard.Enabled {
+		cachedLeaderboardRepository := leaderboardcached.NewRepository(
+			leaderboardRepository,
+			cache.NewLRU(cfg.Cache.Leaderboard.Size, cfg.Cache.Leaderboard.TTL),
+		)
+		leaderboardRepository = cachedLeaderboardRepository
+		invalidators = append(invalidators, cachedLeaderboardRepository)
+		statsInvalidators = append(statsInvalidators, cachedLeaderboardRepository)
+	}
+	// Must remain an untyped nil if caching is disabled
+	var playerInfoStore cache.Store
+	if cfg.Cache.PlayerInfo.Enabled {
+		playerInfoStore = cache.NewLRU(cfg.Cache.PlayerInfo.Size, cfg.Cache.PlayerInfo.TTL)
+	}
+
+	gaih := getawardsinfo.NewHandler(awardRecordRepository)
+	gbih := getbackendinfo.NewHandler(unlockRepository)
+	glbh := getleaderboard.NewHandler(leaderboardRepository, seasonRepository, cat)
+	gpih := getplayerinfo.NewHandler(
+		playerRepository,
+		armyRecordRepository,
+		fieldRecordRepository,
+		killHistoryRecordRepository,
+		kitRecordRepository,
+		vehicleRecordRepository,
+		weaponRecordRepository,
+		cat,
+		playerInfoStore,
+	)
+	invalidators = append(invalidators, gpih)
+	statsInvalidators = append(statsInvalidators, gpih)
+	grih := getrankinfo.NewHandler(playerRepository)
+	unlockPolicy := buildUnlockPolicy(cfg.Unlocks)
+	guih := getunlocksinfo.NewHandler(playerRepository, awardRecordRepository, unlockRecordRepository, unlockPolicy)
+	rnh := ranknotification.NewHandler(playerRepository, invalidators)
+	sfph := searchforplayers.NewHandler(playerRepository)
+	suh := selectunlock.NewHandler(playerRepository, awardRecordRepository, unlockRepository, unlockRecordRepository, unlockPolicy, invalidators, publisher)
+	vph := verifyplayer.NewHandler(playerRepository)
+	aljh := listjobs.NewHandler(r.scheduler)
+	algh := apileaderboard.NewHandler(leaderboardRepository, seasonRepository, cat)
+	aslh := apiseason.NewHandler(seasonRepository)
+	akhh := apikillhistory.NewHandler(killHistoryRecordRepository)
+	aph := apiplayer.NewHandler(playerRepository)
+	ach := apiclan.NewHandler(clanRepository, clanStatsRepository)
+	arh := apiround.NewHandler(roundHistoryRepository, publisher)
+	assh := startseason.NewHandler(seasonRepository, seasonArchiver, statsInvalidators)
+	r.workers = append(r.workers, assh.Run)
+	amch := manageclans.NewHandler(playerRepository, clanRepository)
+	aruh := revokeunlock.NewHandler(
+		playerRepository,
+		unlockRepository,
+		unlockRecordRepository,
+		respecRepository,
+		cfg.Unlocks.Respecs,
+		invalidators,
+		publisher,
+	)
+	asrh := setrespecs.NewHandler(respecRepository, cfg.Unlocks.Respecs)
+	oah, err := openapi.NewHandler()
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up openapi handler: %w", err)
+	}
+
+	// Server registry rarely changes, so there is no need to query it for every request
+	serverResolver := serverauth.NewResolver(serverRepository, time.Minute)
+	limit, err := buildRateLimitMiddlewareFunc(cfg.RateLimit, serverResolver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up rate limiting: %w", err)
+	}
+
+	// Validator is backed by the realm's catalogue, so each realm needs its own
+	validator, err := request.NewValidator(cat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up request validation: %w", err)
+	}
+
+	ipExtractor, err := buildIPExtractor(httpCfg.Proxies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up client address extraction: %w", err)
+	}
+
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.Validator = validator
+	e.IPExtractor = ipExtractor
+	r.handler = e
+	// Error handler is strongly modeled after the default one
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		code := http.StatusInternalServerError
+		message := http.StatusText(code)
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			code = he.Code
+			message = http.StatusText(code)
+		}
+
+		// Send response
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(code)
+		} else if isJSONPath(c.Request().URL.Path) {
+			// Any non-ASP endpoint uses proper status codes
+			err = c.JSON(code, map[string]string{"message": message})
+		} else if errors.Is(err, request.ErrInvalidParameters) {
+			// Original backend reports any invalid parameters as a syntax error
+			err = c.String(http.StatusOK, asp.NewSyntaxErrorResponse().Serialize())
+		} else {
+			// Always return 200/OK to match original GameSpy behaviour.
+			// Note: Logs will contain the "underlying" status code, not 200.
+			err = c.String(http.StatusOK, asp.NewErrorResponseWithMessage(code, message).Serialize())
+		}
+		if err != nil {
+			r.logger.Error().
+				Err(err).
+				Msg("Failed to send error response")
+		}
+
+	}
+	// Attach the realm's logger to every request, so anything logged while handling it is attributed to the realm
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.SetRequest(c.Request().WithContext(r.logger.WithContext(c.Request().Context())))
+			return next(c)
+		}
+	})
+	e.Use(middleware.Recover())
+	if httpCfg.CORS.Enabled {
+		e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+			// Only the public (read-only) JSON API is meant to be used by browsers
+			Skipper: func(c echo.Context) bool {
+				path := c.Request().URL.Path
+				return !strings.HasPrefix(path, "/api/") && path != "/openapi.json"
+			},
+			AllowOrigins: httpCfg.CORS.AllowOrigins,
+			AllowMethods: []string{http.MethodGet, http.MethodHead},
+			MaxAge:       int(httpCfg.CORS.MaxAge.Seconds()),
+		}))
+	}
+	if httpCfg.Gzip.Enabled {
+		e.Use(middleware.GzipWithConfig(middleware.GzipConfig{
+			// Compression would buffer events until enough data is written
+			Skipper: func(c echo.Context) bool {
+				return isStreamPath(c.Request().URL.Path)
+			},
+			Level:     httpCfg.Gzip.Level,
+			MinLength: httpCfg.Gzip.MinLength,
+		}))
+	}
+	e.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
+		// Streams are meant to stay open, which the timeout handler would not allow (it also buffers the response)
+		Skipper: func(c echo.Context) bool {
+			return isStreamPath(c.Request().URL.Path)
+		},
+		Timeout: httpCfg.Timeout,
+		ErrorMessage: asp.NewErrorResponseWithMessage(
+			http.StatusServiceUnavailable,
+			http.StatusText(http.StatusServiceUnavailable),
+		).Serialize(),
+	}))
+	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		LogError:     true,
+		LogRemoteIP:  true,
+		LogMethod:    true,
+		LogURI:       true,
+		LogStatus:    true,
+		LogLatency:   true,
+		LogUserAgent: true,
+		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
+			r.logger.Info().
+				Err(v.Error).
+				Str("remote", v.RemoteIP).
+				Str("method", v.Method).
+				Str("URI", v.URI).
+				Int("status", v.Status).
+				Str("latency", v.Latency.Truncate(time.Millisecond).String()).
+				Str("agent", v.UserAgent).
+				Msg("request")
+
+			return nil
+		},
+	}))
+
+	// Stats endpoints can be queried for archived seasons
+	scope := seasonscope.New(seasonRepository, cfg.Seasons.Hosts)
+
+	g := e.Group("/ASP")
+	g.GET("/getawardsinfo.aspx", gaih.HandleGET, limit("getawardsinfo"), scope)
+	g.GET("/getbackendinfo.aspx", gbih.HandleGET, limit("getbackendinfo"))
+	g.GET("/getleaderboard.aspx", glbh.HandleGET, limit("getleaderboard"), scope)
+	g.GET("/getplayerinfo.aspx", gpih.HandleGET, limit("getplayerinfo"), scope)
+	g.GET("/getrankinfo.aspx", grih.HandleGET, limit("getrankinfo"))
+	g.GET("/getunlocksinfo.aspx", guih.HandleGET, limit("getunlocksinfo"))
+	g.GET("/ranknotification.aspx", rnh.HandleGET, limit("ranknotification"))
+	g.GET("/searchforplayers.aspx", sfph.HandleGET, limit("searchforplayers"))
+	g.GET("/VerifyPlayer.aspx", vph.HandleGET, limit("verifyplayer"))
+	// Any endpoint writing data must only be accessible to registered servers
+	g.POST("/selectunlock.aspx", suh.HandlePOST, serverauth.New(serverResolver))
+
+	v1 := e.Group("/api/v1")
+	v1.GET("/leaderboards/:type", algh.HandleGET, limit("api"), scope)
+	v1.GET("/seasons", aslh.HandleGET, limit("api"))
+	v1.GET("/players", aph.HandleGETSearch, limit("playersearch"), scope)
+	v1.GET("/players/:pid/victims", akhh.HandleGETVictims, limit("api"), scope)
+	v1.GET("/players/:pid/attackers", akhh.HandleGETAttackers, limit("api"), scope)
+	v1.GET("/players/:pid/versus/:other", akhh.HandleGETHeadToHead, limit("api"), scope)
+	v1.GET("/players/:pid/rounds", arh.HandleGETByPlayer, limit("api"), scope)
+	v1.GET("/rounds/:id", arh.HandleGET, limit("api"), scope)
+	// Clans are not archived, so clan stats are only available for the live season
+	v1.GET("/players/:pid/clan", ach.HandleGETByPlayer, limit("api"))
+	v1.GET("/clans", ach.HandleGETLeaderboard, limit("api"))
+	v1.GET("/clans/:id", ach.HandleGET, limit("api"))
+	v1.POST("/rounds", arh.HandlePOST, serverauth.New(serverResolver))
+	if r.bus != nil {
+		aevh := apievents.NewHandler(r.bus, cfg.Stream.Buffer)
+		v1.GET("/events", aevh.HandleGET, limit("api"))
+	}
+
+	// Game servers must not be able to administrate the realm, so admins need to authenticate separately
+	a := e.Group("/admin", adminauth.New(cfg.Admin.Keys))
+	a.GET("/jobs", aljh.HandleGET)
+	a.POST("/seasons", assh.HandlePOST)
+	a.GET("/seasons/start", assh.HandleGETStatus)
+	a.DELETE("/players/:pid/unlocks/:id", aruh.HandleDELETE)
+	a.PUT("/players/:pid/respecs", asrh.HandlePUT)
+	a.POST("/clans", amch.HandlePOST)
+	a.PUT("/clans/:id", amch.HandlePUT)
+	a.DELETE("/clans/:id", amch.HandleDELETE)
+	a.PUT("/clans/:id/members/:pid", amch.HandlePUTMember)
+	a.DELETE("/clans/:id/members/:pid", amch.HandleDELETEMember)
+
+	e.GET("/openapi.json", oah.HandleGET, limit("api"))
+
+	return r, nil
+}
+
+func buildUnlockPolicy(cfg config.UnlocksConfig) unlock.Policy {
+	policy := unlock.DefaultPolicy()
+	if cfg.Enlisted != nil {
+		policy.Enlisted = buildUnlockPool(*cfg.Enlisted)
+	}
+	if cfg.Officer != nil {
+		policy.Officer = buildUnlockPool(*cfg.Officer)
+	}
+	return policy
+}
+
+func buildUnlockPool(cfg config.UnlockPoolConfig) unlock.Pool {
+	grants := make([]unlock.BadgeGrant, 0, len(cfg.Badges))
+	for _, badge := range cfg.Badges {
+		grants = append(grants, unlock.BadgeGrant{
+			AwardID: badge.ID,
+			Level:   badge.Level,
+			Points:  badge.Points,
+		})
+	}
+
+	return unlock.Pool{
+		Unlocks:     cfg.Unlocks,
+		RankGrants:  cfg.Ranks,
+		BadgeGrants: grants,
+	}
+}
+
+func buildWebhooks(cfgs []config.WebhookConfig) ([]webhook.Webhook, error) {
+	webhooks := make([]webhook.Webhook, 0, len(cfgs))
+	names := make(map[string]bool, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("webhook is missing a name: %s", cfg.URL)
+		}
+		if names[cfg.Name] {
+			return nil, fmt.Errorf("duplicate webhook name: %s", cfg.Name)
+		}
+		names[cfg.Name] = true
+
+		u, err := url.Parse(cfg.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("invalid url for webhook %s: %s", cfg.Name, cfg.URL)
+		}
+
+		events := make([]event.Type, 0, len(cfg.Events))
+		for _, e := range cfg.Events {
+			t := event.Type(e)
+			if !slices.Contains(event.Types, t) {
+				return nil, fmt.Errorf("unknown event type for webhook %s: %s", cfg.Name, e)
+			}
+			events = append(events, t)
+		}
+
+		webhooks = append(webhooks, webhook.Webhook{
+			Name:   cfg.Name,
+			URL:    cfg.URL,
+			Secret: cfg.Secret,
+			Events: events,
+		})
+	}
+
+	return webhooks, nil
+}
+
+func buildRateLimitMiddlewareFunc(cfg config.RateLimitConfig, resolver *serverauth.Resolver) (func(endpoint string) echo.MiddlewareFunc, error) {
+	if !cfg.Enabled {
+		return func(endpoint string) echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return next
+			}
+		}, nil
+	}
+
+	allowlist := make([]netip.Prefix, 0, len(cfg.Allowlist))
+	for _, address := range cfg.Allowlist {
+		prefix, err := server.ParseAddress(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist address %q: %w", address, err)
+		}
+		allowlist = append(allowlist, prefix)
+	}
+
+	limits := make(map[string]ratelimit.Limit, len(cfg.Endpoints))
+	for endpoint, limit := range cfg.Endpoints {
+		limits[endpoint] = toLimit(limit)
+	}
+
+	// Only pass resolver if servers should be allowed, since the factory uses it to determine whether to skip or not
+	if !cfg.AllowServers {
+		resolver = nil
+	}
+
+	factory := ratelimit.NewFactory(toLimit(cfg.Default), limits, allowlist, resolver)
+	return factory.For, nil
+}
+
+func toLimit(cfg config.LimitConfig) ratelimit.Limit {
+	return ratelimit.Limit{
+		Rate:      cfg.Rate,
+		Burst:     cfg.Burst,
+		ExpiresIn: cfg.ExpiresIn,
+	}
+}
+
+func buildIPExtractor(cfg config.ProxiesConfig) (echo.IPExtractor, error) {
+	// Without any trusted proxies, forwarded addresses cannot be trusted at all
+	if len(cfg.Trusted) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	// Only trust the configured proxies, not any private or loopback address (as echo does by default)
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, address := range cfg.Trusted {
+		prefix, err := server.ParseAddress(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy address %q: %w", address, err)
+		}
+		options = append(options, echo.TrustIPRange(&net.IPNet{
+			IP:   prefix.Addr().AsSlice(),
+			Mask: net.CIDRMask(prefix.Bits(), prefix.Addr().BitLen()),
+		}))
+	}
+
+	if cfg.Header == "x-real-ip" {
+		return echo.ExtractIPFromRealIPHeader(options...), nil
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+// isStreamPath Whether the path is served as a stream (which must not be buffered)
+func isStreamPath(path string) bool {
+	return path == "/api/v1/events"
+}
+
+func isJSONPath(path string) bool {
+	return strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/admin/") || path == "/openapi.json"
+}
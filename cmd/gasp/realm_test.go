@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cetteup/gasp/cmd/gasp/internal/config"
+	"github.com/cetteup/gasp/cmd/gasp/internal/openapi"
+)
+
+// TestRoutesMatchSpecification Catches routes being added or removed without updating the specification
+func TestRoutesMatchSpecification(t *testing.T) {
+	// Connections are only opened on the first query, so the database does not need to exist
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte("name: test\ndb:\n  host: 127.0.0.1:3306\n  dbname: gasp\nstream:\n  enabled: true\n")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if got := cfg.Realms[0].Database.DatabaseName; got != "gasp" {
+		t.Fatalf("expected database name from config, got %q", got)
+	}
+
+	r, err := buildRealm(cfg.Realms[0], cfg.HTTP)
+	if err != nil {
+		t.Fatalf("failed to build realm: %v", err)
+	}
+	t.Cleanup(r.Close)
+
+	if err = openapi.CheckRoutes(r.handler.Routes()); err != nil {
+		t.Errorf("openapi specification is out of sync with routes: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package bus
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cetteup/gasp/internal/domain/event"
+)
+
+// Bus In-process publish/subscribe bus. Publishing never blocks: events are dropped for subscribers whose buffer is
+// full, since a slow subscriber must not hold up the handlers writing data.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+type Subscription struct {
+	bus     *Bus
+	ch      chan event.Event
+	filter  func(e event.Event) bool
+	dropped atomic.Uint64
+	once    sync.Once
+}
+
+func New() *Bus {
+	return &Bus{
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe Returns a subscription receiving all published events matching the filter (all events if nil)
+func (b *Bus) Subscribe(buffer int, filter func(e event.Event) bool) *Subscription {
+	s := &Subscription{
+		bus:    b,
+		ch:     make(chan event.Event, buffer),
+		filter: filter,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// Subscriptions to a closed bus are closed right away
+	if b.closed {
+		s.once.Do(func() {
+			close(s.ch)
+		})
+		return s
+	}
+	b.subscribers[s] = struct{}{}
+
+	return s
+}
+
+func (b *Bus) Publish(_ context.Context, events ...event.Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for s := range b.subscribers {
+		for _, e := range events {
+			if s.filter != nil && !s.filter(e) {
+				continue
+			}
+
+			select {
+			case s.ch <- e:
+			default:
+				s.dropped.Add(1)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close Closes all subscriptions (e.g. on shutdown), causing subscribers to return
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subscribers {
+		s.once.Do(func() {
+			close(s.ch)
+		})
+	}
+	clear(b.subscribers)
+	b.closed = true
+}
+
+// Events Returns the channel events are received on, which is closed once the subscription or bus is closed
+func (s *Subscription) Events() <-chan event.Event {
+	return s.ch
+}
+
+// Dropped Returns the number of events dropped due to a full buffer
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	delete(s.bus.subscribers, s)
+	s.once.Do(func() {
+		close(s.ch)
+	})
+}
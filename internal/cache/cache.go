@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Store A key-value store for serialized values. Implementations are responsible for expiring values as needed.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte) error
+	Delete(ctx context.Context, key string) error
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// PlayerInvalidator Implemented by anything caching player specific data
+type PlayerInvalidator interface {
+	InvalidatePlayer(ctx context.Context, pid uint32) error
+}
+
+// Invalidators Combines multiple invalidators into one
+type Invalidators []PlayerInvalidator
+
+func (i Invalidators) InvalidatePlayer(ctx context.Context, pid uint32) error {
+	for _, invalidator := range i {
+		if err := invalidator.InvalidatePlayer(ctx, pid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StatsInvalidator Implemented by anything caching stats, which must be dropped entirely once all stats are reset
+type StatsInvalidator interface {
+	InvalidateStats(ctx context.Context) error
+}
+
+// StatsInvalidators Combines multiple invalidators into one, invalidating in order
+type StatsInvalidators []StatsInvalidator
+
+func (i StatsInvalidators) InvalidateStats(ctx context.Context) error {
+	for _, invalidator := range i {
+		if err := invalidator.InvalidateStats(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetOrLoad Returns the cached value for key or loads (and caches) it if no value is cached. Cache errors are only
+// logged, since the value can always be loaded directly.
+func GetOrLoad[T any](ctx context.Context, store Store, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	cached, ok, err := store.Get(ctx, key)
+	if err != nil {
+		log.Ctx(ctx).Warn().
+			Err(err).
+			Str("key", key).
+			Msg("Failed to get value from cache")
+	}
+
+	if ok {
+		var value T
+		if err = json.Unmarshal(cached, &value); err == nil {
+			return value, nil
+		}
+		log.Ctx(ctx).Warn().
+			Err(err).
+			Str("key", key).
+			Msg("Failed to decode cached value")
+	}
+
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		log.Ctx(ctx).Warn().
+			Err(err).
+			Str("key", key).
+			Msg("Failed to encode value for cache")
+		return value, nil
+	}
+
+	if err = store.Set(ctx, key, encoded); err != nil {
+		log.Ctx(ctx).Warn().
+			Err(err).
+			Str("key", key).
+			Msg("Failed to store value in cache")
+	}
+
+	return value, nil
+}
@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRU_Eviction(t *testing.T) {
+	tests := []struct {
+		name string
+		// sets Keys to set, in order
+		sets []string
+		// gets Keys to get after setting, in order (marking them as recently used)
+		gets     []string
+		wantKeys []string
+		wantGone []string
+	}{
+		{
+			name:     "within size",
+			sets:     []string{"a", "b"},
+			wantKeys: []string{"a", "b"},
+		},
+		{
+			name:     "evicts least recently set",
+			sets:     []string{"a", "b", "c", "d"},
+			wantKeys: []string{"b", "c", "d"},
+			wantGone: []string{"a"},
+		},
+		{
+			name:     "get marks as recently used",
+			sets:     []string{"a", "b", "c"},
+			gets:     []string{"a"},
+			wantKeys: []string{"a", "c"},
+			wantGone: []string{"b"},
+		},
+		{
+			name:     "set of existing key marks as recently used",
+			sets:     []string{"a", "b", "c", "a", "d"},
+			wantKeys: []string{"a", "c", "d"},
+			wantGone: []string{"b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := NewLRU(3, time.Minute)
+			for _, key := range tt.sets {
+				if err := c.Set(ctx, key, []byte(key)); err != nil {
+					t.Fatalf("failed to set %q: %v", key, err)
+				}
+			}
+			for _, key := range tt.gets {
+				if _, ok, _ := c.Get(ctx, key); !ok {
+					t.Fatalf("expected %q to be cached", key)
+				}
+			}
+			// Add (and immediately evict) an entry if any get changed the order, so the order is actually tested
+			if len(tt.gets) > 0 {
+				if err := c.Set(ctx, "z", []byte("z")); err != nil {
+					t.Fatalf("failed to set %q: %v", "z", err)
+				}
+			}
+
+			for _, key := range tt.wantKeys {
+				value, ok, err := c.Get(ctx, key)
+				if err != nil || !ok || string(value) != key {
+					t.Errorf("expected %q to be cached, got %q (ok: %t, err: %v)", key, value, ok, err)
+				}
+			}
+			for _, key := range tt.wantGone {
+				if _, ok, _ := c.Get(ctx, key); ok {
+					t.Errorf("expected %q to be evicted", key)
+				}
+			}
+		})
+	}
+}
+
+func TestLRU_TTL(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10, 50*time.Millisecond)
+
+	if err := c.Set(ctx, "expiring", []byte("value")); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	if _, ok, _ := c.Get(ctx, "expiring"); !ok {
+		t.Fatalf("expected value to be cached before ttl")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if _, ok, _ := c.Get(ctx, "expiring"); ok {
+		t.Errorf("expected value to be expired after ttl")
+	}
+	if len(c.entries) != 0 || c.order.Len() != 0 {
+		t.Errorf("expected expired value to be removed, got %d entries", len(c.entries))
+	}
+}
+
+func TestLRU_DeletePrefix(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10, time.Minute)
+	for _, key := range []string{"leaderboard:a", "leaderboard:b", "playerinfo:a"} {
+		if err := c.Set(ctx, key, []byte(key)); err != nil {
+			t.Fatalf("failed to set %q: %v", key, err)
+		}
+	}
+
+	if err := c.DeletePrefix(ctx, "leaderboard:"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, key := range []string{"leaderboard:a", "leaderboard:b"} {
+		if _, ok, _ := c.Get(ctx, key); ok {
+			t.Errorf("expected %q to be deleted", key)
+		}
+	}
+	if _, ok, _ := c.Get(ctx, "playerinfo:a"); !ok {
+		t.Errorf("expected %q to be kept", "playerinfo:a")
+	}
+}
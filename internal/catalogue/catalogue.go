@@ -0,0 +1,265 @@
+// Package catalogue describes the armies, fields, kits, vehicles and weapons known to a mod, along with how their
+// (database) ids map to the ids used by the ASP
+package catalogue
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed bf2.yaml
+var bf2 []byte
+
+// Catalogue Armies, fields, kits and vehicles are listed in ASP order, meaning the order in which the game expects
+// them in getplayerinfo responses. Weapons are always reported in ascending order of their ASP ids.
+type Catalogue struct {
+	Name     string    `yaml:"name"`
+	Armies   []Army    `yaml:"armies"`
+	Fields   []Field   `yaml:"fields"`
+	Kits     []Kit     `yaml:"kits"`
+	Vehicles []Vehicle `yaml:"vehicles"`
+	Weapons  Weapons   `yaml:"weapons"`
+
+	// Derived ids, computed once on load
+	armyIDs         []uint8
+	fieldIDs        []uint16
+	aspFieldIDs     []uint16
+	kitIDs          []uint8
+	vehicleIDs      []uint8
+	weaponIDs       []uint8
+	aspWeaponIDs    []uint8
+	aspEquipmentIDs []uint8
+}
+
+type Army struct {
+	ID   uint8  `yaml:"id"`
+	Name string `yaml:"name"`
+}
+
+type Field struct {
+	ID uint16 `yaml:"id"`
+	// ASPID Only needs to be set if the id used by the ASP differs from the id used in the database
+	ASPID *uint16 `yaml:"aspid"`
+	Name  string  `yaml:"name"`
+}
+
+// ASP Returns the id used by the ASP
+func (f Field) ASP() uint16 {
+	if f.ASPID != nil {
+		return *f.ASPID
+	}
+	return f.ID
+}
+
+type Kit struct {
+	ID   uint8  `yaml:"id"`
+	Name string `yaml:"name"`
+}
+
+type Vehicle struct {
+	ID   uint8  `yaml:"id"`
+	Name string `yaml:"name"`
+	// Leaderboard Whether the ASP offers a leaderboard for the vehicle
+	Leaderboard bool `yaml:"leaderboard"`
+}
+
+type Weapons struct {
+	// Explosives ASP id of the weapon all explosives are grouped into
+	Explosives uint8 `yaml:"explosives"`
+	// Dummies ASP ids of weapons without any database equivalent, which still need to be reported (as zeroes)
+	Dummies []uint8  `yaml:"dummies"`
+	Entries []Weapon `yaml:"entries"`
+}
+
+type Weapon struct {
+	ID   uint8  `yaml:"id"`
+	Name string `yaml:"name"`
+	// Explosive Explosives are grouped into a single weapon in the ASP (see Weapons.Explosives)
+	Explosive bool `yaml:"explosive"`
+	// Equipment ASP equipment id, if set the weapon is reported as equipment rather than as a weapon
+	Equipment *uint8 `yaml:"equipment"`
+	// Leaderboard Whether the ASP offers a leaderboard for the weapon
+	Leaderboard bool `yaml:"leaderboard"`
+}
+
+// Load Loads the catalogue from the given definition file, or the built-in (vanilla BF2) catalogue if path is empty
+func Load(path string) (*Catalogue, error) {
+	content := bf2
+	if path != "" {
+		var err error
+		content, err = os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var c Catalogue
+	if err := yaml.Unmarshal(content, &c); err != nil {
+		return nil, err
+	}
+
+	if err := c.init(); err != nil {
+		return nil, fmt.Errorf("invalid catalogue %q: %w", c.Name, err)
+	}
+
+	return &c, nil
+}
+
+// init Validates the catalogue and computes the derived ids
+func (c *Catalogue) init() error {
+	c.armyIDs = make([]uint8, 0, len(c.Armies))
+	for _, a := range c.Armies {
+		if slices.Contains(c.armyIDs, a.ID) {
+			return fmt.Errorf("duplicate army id: %d", a.ID)
+		}
+		c.armyIDs = append(c.armyIDs, a.ID)
+	}
+
+	c.fieldIDs = make([]uint16, 0, len(c.Fields))
+	c.aspFieldIDs = make([]uint16, 0, len(c.Fields))
+	for _, f := range c.Fields {
+		if slices.Contains(c.fieldIDs, f.ID) {
+			return fmt.Errorf("duplicate field id: %d", f.ID)
+		}
+		if slices.Contains(c.aspFieldIDs, f.ASP()) {
+			return fmt.Errorf("duplicate field asp id: %d", f.ASP())
+		}
+		c.fieldIDs = append(c.fieldIDs, f.ID)
+		c.aspFieldIDs = append(c.aspFieldIDs, f.ASP())
+	}
+
+	c.kitIDs = make([]uint8, 0, len(c.Kits))
+	for _, k := range c.Kits {
+		if slices.Contains(c.kitIDs, k.ID) {
+			return fmt.Errorf("duplicate kit id: %d", k.ID)
+		}
+		c.kitIDs = append(c.kitIDs, k.ID)
+	}
+
+	c.vehicleIDs = make([]uint8, 0, len(c.Vehicles))
+	for _, v := range c.Vehicles {
+		if slices.Contains(c.vehicleIDs, v.ID) {
+			return fmt.Errorf("duplicate vehicle id: %d", v.ID)
+		}
+		c.vehicleIDs = append(c.vehicleIDs, v.ID)
+	}
+
+	c.weaponIDs = make([]uint8, 0, len(c.Weapons.Entries))
+	c.aspWeaponIDs = slices.Clone(c.Weapons.Dummies)
+	c.aspEquipmentIDs = make([]uint8, 0)
+	var hasExplosives bool
+	for _, w := range c.Weapons.Entries {
+		if slices.Contains(c.weaponIDs, w.ID) {
+			return fmt.Errorf("duplicate weapon id: %d", w.ID)
+		}
+		c.weaponIDs = append(c.weaponIDs, w.ID)
+
+		switch {
+		case w.Explosive && w.Equipment != nil:
+			return fmt.Errorf("weapon cannot be both explosive and equipment: %d", w.ID)
+		case w.Explosive:
+			hasExplosives = true
+		case w.Equipment != nil:
+			if slices.Contains(c.aspEquipmentIDs, *w.Equipment) {
+				return fmt.Errorf("duplicate equipment asp id: %d", *w.Equipment)
+			}
+			c.aspEquipmentIDs = append(c.aspEquipmentIDs, *w.Equipment)
+		default:
+			if slices.Contains(c.aspWeaponIDs, w.ID) || hasExplosives && w.ID == c.Weapons.Explosives {
+				return fmt.Errorf("weapon asp id already in use: %d", w.ID)
+			}
+			c.aspWeaponIDs = append(c.aspWeaponIDs, w.ID)
+		}
+	}
+
+	if hasExplosives {
+		if slices.Contains(c.aspWeaponIDs, c.Weapons.Explosives) {
+			return fmt.Errorf("explosives asp id already in use: %d", c.Weapons.Explosives)
+		}
+		c.aspWeaponIDs = append(c.aspWeaponIDs, c.Weapons.Explosives)
+	}
+
+	slices.Sort(c.aspWeaponIDs)
+	slices.Sort(c.aspEquipmentIDs)
+
+	// Derived ids are shared, so make sure appending to them never writes to the shared backing arrays
+	c.aspWeaponIDs = slices.Clip(c.aspWeaponIDs)
+	c.aspEquipmentIDs = slices.Clip(c.aspEquipmentIDs)
+
+	return nil
+}
+
+// ArmyIDs Returns the army ids (identical in the database and the ASP) in ASP order
+func (c *Catalogue) ArmyIDs() []uint8 {
+	return c.armyIDs
+}
+
+// FieldIDs Returns the field ids used in the database
+func (c *Catalogue) FieldIDs() []uint16 {
+	return c.fieldIDs
+}
+
+// ASPFieldIDs Returns the field ids used by the ASP in ASP order
+func (c *Catalogue) ASPFieldIDs() []uint16 {
+	return c.aspFieldIDs
+}
+
+// FieldFromASP Translates a field id used by the ASP to the id used in the database
+func (c *Catalogue) FieldFromASP(aspID uint16) (uint16, bool) {
+	i := slices.Index(c.aspFieldIDs, aspID)
+	if i == -1 {
+		return 0, false
+	}
+	return c.fieldIDs[i], true
+}
+
+// KitIDs Returns the kit ids (identical in the database and the ASP) in ASP order
+func (c *Catalogue) KitIDs() []uint8 {
+	return c.kitIDs
+}
+
+// VehicleIDs Returns the vehicle ids (identical in the database and the ASP) in ASP order
+func (c *Catalogue) VehicleIDs() []uint8 {
+	return c.vehicleIDs
+}
+
+// VehicleLeaderboardIDs Returns the ids of vehicles the ASP offers a leaderboard for
+func (c *Catalogue) VehicleLeaderboardIDs() []uint8 {
+	ids := make([]uint8, 0, len(c.Vehicles))
+	for _, v := range c.Vehicles {
+		if v.Leaderboard {
+			ids = append(ids, v.ID)
+		}
+	}
+	return ids
+}
+
+// WeaponIDs Returns the weapon ids used in the database
+func (c *Catalogue) WeaponIDs() []uint8 {
+	return c.weaponIDs
+}
+
+// ASPWeaponIDs Returns the weapon ids used by the ASP, including the explosives group and any dummies
+func (c *Catalogue) ASPWeaponIDs() []uint8 {
+	return c.aspWeaponIDs
+}
+
+// ASPEquipmentIDs Returns the equipment ids used by the ASP
+func (c *Catalogue) ASPEquipmentIDs() []uint8 {
+	return c.aspEquipmentIDs
+}
+
+// WeaponLeaderboardIDs Returns the ids of weapons the ASP offers a leaderboard for
+func (c *Catalogue) WeaponLeaderboardIDs() []uint8 {
+	ids := make([]uint8, 0, len(c.Weapons.Entries))
+	for _, w := range c.Weapons.Entries {
+		if w.Leaderboard {
+			ids = append(ids, w.ID)
+		}
+	}
+	return ids
+}
@@ -23,21 +23,6 @@ const (
 	EU uint8 = 9
 )
 
-var (
-	IDs = []uint8{
-		USMC,
-		MEC,
-		PLA,
-		NavySeals,
-		SAS,
-		Spetznas,
-		MECSF,
-		Rebels,
-		Insurgents,
-		EU,
-	}
-)
-
 type Record struct {
 	Player          PlayerRef
 	Army            ArmyRef
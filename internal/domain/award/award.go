@@ -1,6 +1,8 @@
 package award
 
 import (
+	"slices"
+
 	"github.com/cetteup/gasp/internal/domain/round"
 )
 
@@ -29,18 +31,17 @@ type PlayerRef struct {
 	ID uint32
 }
 
+// KitBadgeIDs IDs of the kit badges (one per kit)
+var KitBadgeIDs = []uint32{
+	1031119, // Assault
+	1031120, // Anti-tank
+	1031109, // Sniper
+	1031115, // Spec-Ops
+	1031121, // Support
+	1031105, // Engineer
+	1031113, // Medic
+}
+
 func IsKitBadge(awardID uint32) bool {
-	switch awardID {
-	case
-		1031119, // Assault
-		1031120, // Anti-tank
-		1031109, // Sniper
-		1031115, // Spec-Ops
-		1031121, // Support
-		1031105, // Engineer
-		1031113: // Medic
-		return true
-	default:
-		return false
-	}
+	return slices.Contains(KitBadgeIDs, awardID)
 }
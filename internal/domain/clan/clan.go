@@ -0,0 +1,72 @@
+package clan
+
+type Role uint8
+
+const (
+	RoleMember Role = iota
+	RoleOfficer
+	RoleLeader
+)
+
+var roleNames = map[Role]string{
+	RoleMember:  "member",
+	RoleOfficer: "officer",
+	RoleLeader:  "leader",
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseRole Returns the role with the given name (as returned by Role.String)
+func ParseRole(name string) (Role, bool) {
+	for role, n := range roleNames {
+		if n == name {
+			return role, true
+		}
+	}
+	return 0, false
+}
+
+type Clan struct {
+	ID uint32
+	// Tag Prefix members put in front of their nick (e.g. "=TAG="), unique across clans
+	Tag  string
+	Name string
+	// Created Will overflow on 7 February 2106 at 06:28:15 UTC
+	Created uint32
+	Members []Member
+}
+
+type Member struct {
+	Player PlayerRef
+	Role   Role
+	// Joined Will overflow on 7 February 2106 at 06:28:15 UTC
+	Joined uint32
+}
+
+type PlayerRef struct {
+	ID   uint32
+	Name string
+}
+
+type Ref struct {
+	ID   uint32
+	Tag  string
+	Name string
+}
+
+// Stats Stats of all members combined
+type Stats struct {
+	Clan    Ref
+	Members int
+	Score   int64
+	Kills   uint64
+	Deaths  uint64
+	Time    uint64
+	// AverageRank Average rank id of all members, zero for clans without members
+	AverageRank float64
+}
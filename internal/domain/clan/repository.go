@@ -0,0 +1,45 @@
+package clan
+
+import (
+	"context"
+	"errors"
+)
+
+// RankBy Clan leaderboards can be ranked by different (combined) stats
+type RankBy int
+
+const (
+	RankByScore RankBy = iota
+	RankByKills
+	RankByTime
+	RankByAverageRank
+)
+
+var (
+	ErrClanNotFound   = errors.New("clan not found")
+	ErrDuplicateTag   = errors.New("clan tag already in use")
+	ErrMemberNotFound = errors.New("clan member not found")
+	ErrAlreadyInAClan = errors.New("player is already a member of a clan")
+)
+
+type Repository interface {
+	// FindByID Returns the clan including its members
+	FindByID(ctx context.Context, id uint32) (Clan, error)
+	// FindByPlayerID Returns the clan the player is a member of, including its members
+	FindByPlayerID(ctx context.Context, playerID uint32) (Clan, error)
+	// Insert Adds the clan (without members), returning its id
+	Insert(ctx context.Context, c Clan) (uint32, error)
+	// Update Updates the clan's tag and name
+	Update(ctx context.Context, c Clan) error
+	// Delete Deletes the clan along with all memberships
+	Delete(ctx context.Context, id uint32) error
+	// SetMember Adds the player to the clan or updates the player's role if they already are a member
+	SetMember(ctx context.Context, clanID uint32, member Member) error
+	RemoveMember(ctx context.Context, clanID uint32, playerID uint32) error
+}
+
+type StatsRepository interface {
+	FindByClanID(ctx context.Context, id uint32) (Stats, error)
+	// FindTop Returns the requested page of the clan leaderboard, along with the total number of clans
+	FindTop(ctx context.Context, by RankBy, offset, limit uint32) ([]Stats, int, error)
+}
@@ -0,0 +1,334 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/cetteup/gasp/internal/domain/clan"
+	"github.com/cetteup/gasp/internal/sqlutil"
+)
+
+const (
+	clanTable   = "clan"
+	memberTable = "clan_member"
+	playerTable = "player"
+
+	columnID       = "id"
+	columnTag      = "tag"
+	columnName     = "name"
+	columnCreated  = "created"
+	columnClanID   = "clan_id"
+	columnPlayerID = "player_id"
+	columnRole     = "role"
+	columnJoined   = "joined"
+	columnScore    = "score"
+	columnKills    = "kills"
+	columnDeaths   = "deaths"
+	columnTime     = "time"
+	columnRankID   = "rank_id"
+)
+
+type Repository struct {
+	runner sqlutil.TxRunner
+}
+
+func NewRepository(runner sqlutil.TxRunner) *Repository {
+	return &Repository{
+		runner: runner,
+	}
+}
+
+func (r *Repository) FindByID(ctx context.Context, id uint32) (clan.Clan, error) {
+	return r.findOne(ctx, sq.Eq{sqlutil.Qualify(clanTable, columnID): id})
+}
+
+func (r *Repository) FindByPlayerID(ctx context.Context, playerID uint32) (clan.Clan, error) {
+	membership := sq.
+		Select(columnClanID).
+		From(memberTable).
+		Where(sq.Eq{columnPlayerID: playerID})
+
+	return r.findOne(ctx, sq.Expr(sqlutil.Qualify(clanTable, columnID)+" IN (?)", membership))
+}
+
+func (r *Repository) Insert(ctx context.Context, c clan.Clan) (uint32, error) {
+	query := sq.
+		Insert(clanTable).
+		Columns(
+			columnTag,
+			columnName,
+			columnCreated,
+		).
+		Values(
+			c.Tag,
+			c.Name,
+			c.Created,
+		)
+
+	res, err := query.RunWith(r.runner).ExecContext(ctx)
+	if err != nil {
+		if sqlutil.IsDuplicateEntry(err) {
+			return 0, clan.ErrDuplicateTag
+		}
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(id), nil
+}
+
+func (r *Repository) Update(ctx context.Context, c clan.Clan) error {
+	query := sq.
+		Update(clanTable).
+		Set(columnTag, c.Tag).
+		Set(columnName, c.Name).
+		Where(sq.Eq{columnID: c.ID})
+
+	res, err := query.RunWith(r.runner).ExecContext(ctx)
+	if err != nil {
+		if sqlutil.IsDuplicateEntry(err) {
+			return clan.ErrDuplicateTag
+		}
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// Rows are not "affected" if the values did not change, so make sure the clan actually does not exist
+	if affected == 0 {
+		_, err = r.FindByID(ctx, c.ID)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id uint32) error {
+	tx, err := r.runner.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback is a noop if the transaction has already been committed
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = sq.
+		Delete(memberTable).
+		Where(sq.Eq{columnClanID: id}).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete clan members: %w", err)
+	}
+
+	res, err := sq.
+		Delete(clanTable).
+		Where(sq.Eq{columnID: id}).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete clan: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return clan.ErrClanNotFound
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) SetMember(ctx context.Context, clanID uint32, member clan.Member) error {
+	tx, err := r.runner.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback is a noop if the transaction has already been committed
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// Lock the clan row to make sure it is not deleted while adding the member
+	var exists bool
+	err = sq.
+		Select("1").
+		From(clanTable).
+		Where(sq.Eq{columnID: clanID}).
+		Suffix("FOR UPDATE").
+		RunWith(tx).
+		QueryRowContext(ctx).
+		Scan(&exists)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return clan.ErrClanNotFound
+		}
+		return err
+	}
+
+	// Player id is unique, so a player who is a member of another clan causes a duplicate entry (which is not updated,
+	// since the clan id does not match)
+	_, err = sq.
+		Insert(memberTable).
+		Columns(
+			columnClanID,
+			columnPlayerID,
+			columnRole,
+			columnJoined,
+		).
+		Values(
+			clanID,
+			member.Player.ID,
+			member.Role,
+			member.Joined,
+		).
+		Suffix(fmt.Sprintf(
+			"ON DUPLICATE KEY UPDATE %[1]s = IF(%[2]s = VALUES(%[2]s), VALUES(%[1]s), %[1]s)",
+			columnRole,
+			columnClanID,
+		)).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to insert clan member: %w", err)
+	}
+
+	var current uint32
+	err = sq.
+		Select(columnClanID).
+		From(memberTable).
+		Where(sq.Eq{columnPlayerID: member.Player.ID}).
+		RunWith(tx).
+		QueryRowContext(ctx).
+		Scan(&current)
+	if err != nil {
+		return fmt.Errorf("failed to find clan membership: %w", err)
+	}
+
+	if current != clanID {
+		return clan.ErrAlreadyInAClan
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) RemoveMember(ctx context.Context, clanID uint32, playerID uint32) error {
+	query := sq.
+		Delete(memberTable).
+		Where(sq.Eq{
+			columnClanID:   clanID,
+			columnPlayerID: playerID,
+		})
+
+	res, err := query.RunWith(r.runner).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return clan.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) findOne(ctx context.Context, pred any) (clan.Clan, error) {
+	query := sq.
+		Select(
+			sqlutil.Qualify(clanTable, columnID),
+			sqlutil.Qualify(clanTable, columnTag),
+			sqlutil.Qualify(clanTable, columnName),
+			sqlutil.Qualify(clanTable, columnCreated),
+		).
+		From(clanTable).
+		Where(pred)
+
+	var c clan.Clan
+	if err := query.RunWith(r.runner).QueryRowContext(ctx).Scan(
+		&c.ID,
+		&c.Tag,
+		&c.Name,
+		&c.Created,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return clan.Clan{}, clan.ErrClanNotFound
+		}
+		return clan.Clan{}, err
+	}
+
+	members, err := r.findMembers(ctx, c.ID)
+	if err != nil {
+		return clan.Clan{}, fmt.Errorf("failed to find clan members: %w", err)
+	}
+	c.Members = members
+
+	return c, nil
+}
+
+func (r *Repository) findMembers(ctx context.Context, clanID uint32) ([]clan.Member, error) {
+	query := sq.
+		Select(
+			sqlutil.Qualify(memberTable, columnPlayerID),
+			sqlutil.Qualify(playerTable, columnName),
+			sqlutil.Qualify(memberTable, columnRole),
+			sqlutil.Qualify(memberTable, columnJoined),
+		).
+		From(memberTable).
+		InnerJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			playerTable,
+			sqlutil.Qualify(memberTable, columnPlayerID),
+			sqlutil.Qualify(playerTable, columnID),
+		)).
+		Where(sq.Eq{sqlutil.Qualify(memberTable, columnClanID): clanID}).
+		OrderBy(
+			fmt.Sprintf("%s DESC", sqlutil.Qualify(memberTable, columnRole)),
+			fmt.Sprintf("%s ASC", sqlutil.Qualify(memberTable, columnJoined)),
+		)
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]clan.Member, 0)
+	for rows.Next() {
+		var m clan.Member
+		if err = rows.Scan(
+			&m.Player.ID,
+			&m.Player.Name,
+			&m.Role,
+			&m.Joined,
+		); err != nil {
+			return nil, err
+		}
+
+		members = append(members, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
@@ -0,0 +1,155 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/cetteup/gasp/internal/domain/clan"
+	"github.com/cetteup/gasp/internal/sqlutil"
+)
+
+// Aliases of the aggregated columns, used for ordering
+const (
+	aliasMembers     = "members"
+	aliasScore       = "total_score"
+	aliasKills       = "total_kills"
+	aliasDeaths      = "total_deaths"
+	aliasTime        = "total_time"
+	aliasAverageRank = "average_rank"
+)
+
+// StatsRepository Aggregates clan stats from the members' player records
+type StatsRepository struct {
+	runner sq.BaseRunner
+}
+
+func NewStatsRepository(runner sq.BaseRunner) *StatsRepository {
+	return &StatsRepository{
+		runner: runner,
+	}
+}
+
+func (r *StatsRepository) FindByClanID(ctx context.Context, id uint32) (clan.Stats, error) {
+	query := buildStatsQuery().
+		Where(sq.Eq{sqlutil.Qualify(clanTable, columnID): id})
+
+	var s clan.Stats
+	if err := scanStats(query.RunWith(r.runner).QueryRowContext(ctx), &s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return clan.Stats{}, clan.ErrClanNotFound
+		}
+		return clan.Stats{}, err
+	}
+
+	return s, nil
+}
+
+func (r *StatsRepository) FindTop(ctx context.Context, by clan.RankBy, offset, limit uint32) ([]clan.Stats, int, error) {
+	var orderBy string
+	switch by {
+	case clan.RankByScore:
+		orderBy = aliasScore
+	case clan.RankByKills:
+		orderBy = aliasKills
+	case clan.RankByTime:
+		orderBy = aliasTime
+	case clan.RankByAverageRank:
+		orderBy = aliasAverageRank
+	default:
+		return nil, 0, fmt.Errorf("unknown clan rank by: %d", by)
+	}
+
+	count := sq.
+		Select("COUNT(*)").
+		From(clanTable)
+
+	var total int
+	if err := count.RunWith(r.runner).QueryRowContext(ctx).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := buildStatsQuery().
+		OrderBy(
+			fmt.Sprintf("%s DESC", orderBy),
+			fmt.Sprintf("%s ASC", sqlutil.Qualify(clanTable, columnID)),
+		).
+		Offset(uint64(offset)).
+		Limit(uint64(limit))
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stats := make([]clan.Stats, 0, limit)
+	for rows.Next() {
+		var s clan.Stats
+		if err = scanStats(rows, &s); err != nil {
+			return nil, 0, err
+		}
+
+		stats = append(stats, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return stats, total, nil
+}
+
+func buildStatsQuery() sq.SelectBuilder {
+	sum := func(column, alias string) string {
+		return fmt.Sprintf("COALESCE(SUM(%s), 0) AS %s", sqlutil.Qualify(playerTable, column), alias)
+	}
+
+	return sq.
+		Select(
+			sqlutil.Qualify(clanTable, columnID),
+			sqlutil.Qualify(clanTable, columnTag),
+			sqlutil.Qualify(clanTable, columnName),
+			fmt.Sprintf("COUNT(%s) AS %s", sqlutil.Qualify(playerTable, columnID), aliasMembers),
+			sum(columnScore, aliasScore),
+			sum(columnKills, aliasKills),
+			sum(columnDeaths, aliasDeaths),
+			sum(columnTime, aliasTime),
+			fmt.Sprintf("COALESCE(AVG(%s), 0) AS %s", sqlutil.Qualify(playerTable, columnRankID), aliasAverageRank),
+		).
+		From(clanTable).
+		// Left join to include clans without any members
+		LeftJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			memberTable,
+			sqlutil.Qualify(clanTable, columnID),
+			sqlutil.Qualify(memberTable, columnClanID),
+		)).
+		LeftJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			playerTable,
+			sqlutil.Qualify(memberTable, columnPlayerID),
+			sqlutil.Qualify(playerTable, columnID),
+		)).
+		GroupBy(
+			sqlutil.Qualify(clanTable, columnID),
+			sqlutil.Qualify(clanTable, columnTag),
+			sqlutil.Qualify(clanTable, columnName),
+		)
+}
+
+func scanStats(scanner sq.RowScanner, s *clan.Stats) error {
+	return scanner.Scan(
+		&s.Clan.ID,
+		&s.Clan.Tag,
+		&s.Clan.Name,
+		&s.Members,
+		&s.Score,
+		&s.Kills,
+		&s.Deaths,
+		&s.Time,
+		&s.AverageRank,
+	)
+}
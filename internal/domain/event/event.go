@@ -0,0 +1,134 @@
+package event
+
+import (
+	"context"
+	"slices"
+
+	"go.uber.org/multierr"
+)
+
+type Type string
+
+const (
+	TypeRankChanged    Type = "rank.changed"
+	TypeAwardEarned    Type = "award.earned"
+	TypeUnlockSelected Type = "unlock.selected"
+	TypeUnlockRevoked  Type = "unlock.revoked"
+	TypePlayerBanned   Type = "player.banned"
+	TypeRoundIngested  Type = "round.ingested"
+	// TypeLeaderboardChanged Top 10 of the overall score leaderboard changed
+	TypeLeaderboardChanged Type = "leaderboard.changed"
+)
+
+// Types All known event types
+var Types = []Type{
+	TypeRankChanged,
+	TypeAwardEarned,
+	TypeUnlockSelected,
+	TypeUnlockRevoked,
+	TypePlayerBanned,
+	TypeRoundIngested,
+	TypeLeaderboardChanged,
+}
+
+// Event Something that happened to a player, a round or a leaderboard. Only the details matching the type are set,
+// player is empty for events not related to a single player.
+type Event struct {
+	Type   Type
+	Player PlayerRef
+	// Timestamp Will overflow on 7 February 2106 at 06:28:15 UTC
+	Timestamp   uint32
+	Rank        *RankChange
+	Award       *AwardEarned
+	Unlock      *UnlockChange
+	Ban         *Ban
+	Round       *RoundIngested
+	Leaderboard *LeaderboardChange
+}
+
+// Involves Returns whether the player is the subject of the event, took part in the round or is on the leaderboard
+func (e Event) Involves(pid uint32) bool {
+	if e.Player.ID == pid {
+		return true
+	}
+	if e.Round != nil && slices.Contains(e.Round.PlayerIDs, pid) {
+		return true
+	}
+	if e.Leaderboard != nil && slices.ContainsFunc(e.Leaderboard.Entries, func(entry LeaderboardEntry) bool {
+		return entry.Player.ID == pid
+	}) {
+		return true
+	}
+	return false
+}
+
+type PlayerRef struct {
+	ID   uint32
+	Name string
+}
+
+type RankChange struct {
+	ID        uint8
+	Decreased bool
+}
+
+type AwardEarned struct {
+	ID      uint32
+	Level   uint64
+	RoundID uint32
+}
+
+// UnlockChange Unlock which was selected or revoked
+type UnlockChange struct {
+	ID uint16
+}
+
+type Ban struct {
+	TimesBanned uint16
+	Permanent   bool
+}
+
+type RoundIngested struct {
+	ID        uint32
+	ServerID  uint32
+	FieldID   uint16
+	PlayerIDs []uint32
+}
+
+type LeaderboardChange struct {
+	// Entries The new top 10
+	Entries []LeaderboardEntry
+}
+
+type LeaderboardEntry struct {
+	Position uint32
+	Player   PlayerRef
+	Score    int64
+}
+
+// Delivery An event queued for delivery to a webhook (an outbox entry)
+type Delivery struct {
+	ID      uint64
+	Webhook string
+	Type    Type
+	// Payload Encoded event, exactly as it is to be delivered
+	Payload []byte
+	// Created Will overflow on 7 February 2106 at 06:28:15 UTC
+	Created  uint32
+	Attempts uint16
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, events ...Event) error
+}
+
+// Publishers Combines multiple publishers into one, publishing to all of them even if any fail
+type Publishers []Publisher
+
+func (p Publishers) Publish(ctx context.Context, events ...Event) error {
+	var err error
+	for _, publisher := range p {
+		err = multierr.Append(err, publisher.Publish(ctx, events...))
+	}
+	return err
+}
@@ -0,0 +1,23 @@
+package event
+
+import (
+	"context"
+)
+
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, deliveries []Delivery) error
+	// FindDue Returns pending deliveries whose next attempt is due at the given timestamp, oldest first
+	FindDue(ctx context.Context, timestamp uint32, limit uint32) ([]Delivery, error)
+	MarkDelivered(ctx context.Context, id uint64, timestamp uint32) error
+	// MarkFailed Records a failed attempt, giving up on the delivery if nextAttempt is zero
+	MarkFailed(ctx context.Context, id uint64, attempts uint16, nextAttempt uint32, reason string) error
+}
+
+// WatchRepository Detects events from changes to the stats, which are written by other processes
+type WatchRepository interface {
+	// FindUnseen Returns rank changes, awards and bans which have not been marked as seen. The first call only records
+	// the current state as seen (without returning any events), to not report any past events.
+	FindUnseen(ctx context.Context, timestamp uint32) ([]Event, error)
+	// MarkSeen Records the players' current ranks and bans as well as the events' awards as seen
+	MarkSeen(ctx context.Context, events []Event) error
+}
@@ -0,0 +1,146 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/cetteup/gasp/internal/domain/event"
+)
+
+const (
+	outboxTable = "event_outbox"
+
+	columnID          = "id"
+	columnWebhook     = "webhook"
+	columnType        = "type"
+	columnPayload     = "payload"
+	columnCreated     = "created"
+	columnAttempts    = "attempts"
+	columnNextAttempt = "next_attempt"
+	columnDelivered   = "delivered"
+	columnLastError   = "last_error"
+)
+
+// OutboxRepository Persists deliveries until they have been delivered (or given up on), so they survive restarts
+type OutboxRepository struct {
+	runner sq.BaseRunner
+}
+
+func NewOutboxRepository(runner sq.BaseRunner) *OutboxRepository {
+	return &OutboxRepository{
+		runner: runner,
+	}
+}
+
+func (r *OutboxRepository) Enqueue(ctx context.Context, deliveries []event.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	query := sq.
+		Insert(outboxTable).
+		Columns(
+			columnWebhook,
+			columnType,
+			columnPayload,
+			columnCreated,
+			columnAttempts,
+			columnNextAttempt,
+			columnDelivered,
+			columnLastError,
+		)
+	for _, d := range deliveries {
+		// First attempt is due right away
+		query = query.Values(d.Webhook, d.Type, d.Payload, d.Created, 0, d.Created, 0, "")
+	}
+
+	_, err := query.RunWith(r.runner).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) FindDue(ctx context.Context, timestamp uint32, limit uint32) ([]event.Delivery, error) {
+	query := sq.
+		Select(
+			columnID,
+			columnWebhook,
+			columnType,
+			columnPayload,
+			columnCreated,
+			columnAttempts,
+		).
+		From(outboxTable).
+		Where(sq.And{
+			sq.Eq{columnDelivered: 0},
+			// Next attempt is zero for deliveries which have been given up on
+			sq.Gt{columnNextAttempt: 0},
+			sq.LtOrEq{columnNextAttempt: timestamp},
+		}).
+		OrderBy(fmt.Sprintf("%s ASC", columnID)).
+		Limit(uint64(limit))
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]event.Delivery, 0, limit)
+	for rows.Next() {
+		var d event.Delivery
+		if err = rows.Scan(
+			&d.ID,
+			&d.Webhook,
+			&d.Type,
+			&d.Payload,
+			&d.Created,
+			&d.Attempts,
+		); err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, id uint64, timestamp uint32) error {
+	query := sq.
+		Update(outboxTable).
+		Set(columnAttempts, sq.Expr(columnAttempts+" + 1")).
+		Set(columnDelivered, timestamp).
+		Set(columnLastError, "").
+		Where(sq.Eq{columnID: id})
+
+	_, err := query.RunWith(r.runner).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uint64, attempts uint16, nextAttempt uint32, reason string) error {
+	query := sq.
+		Update(outboxTable).
+		Set(columnAttempts, attempts).
+		Set(columnNextAttempt, nextAttempt).
+		Set(columnLastError, reason).
+		Where(sq.Eq{columnID: id})
+
+	_, err := query.RunWith(r.runner).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
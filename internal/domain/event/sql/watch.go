@@ -0,0 +1,347 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/cetteup/gasp/internal/domain/event"
+	"github.com/cetteup/gasp/internal/sqlutil"
+)
+
+const (
+	playerStateTable = "event_player_state"
+	watermarkTable   = "event_watermark"
+	playerTable      = "player"
+	awardRecordTable = "player_award"
+
+	columnPlayerID      = "player_id"
+	columnName          = "name"
+	columnValue         = "value"
+	columnRankID        = "rank_id"
+	columnRankChanged   = "chng"
+	columnRankDecreased = "decr"
+	columnTimesBanned   = "banned"
+	columnPermanentBan  = "permban"
+	columnAwardID       = "award_id"
+	columnLevel         = "level"
+	columnRoundID       = "round_id"
+
+	// Awards are detected by the round they were earned in, rounds with a higher id are considered unseen
+	watermarkAward = "award"
+)
+
+// WatchRepository Detects events by comparing the players' current ranks and bans to those last seen
+type WatchRepository struct {
+	runner sqlutil.TxRunner
+}
+
+func NewWatchRepository(runner sqlutil.TxRunner) *WatchRepository {
+	return &WatchRepository{
+		runner: runner,
+	}
+}
+
+func (r *WatchRepository) FindUnseen(ctx context.Context, timestamp uint32) ([]event.Event, error) {
+	var watermark uint32
+	err := sq.
+		Select(columnValue).
+		From(watermarkTable).
+		Where(sq.Eq{columnName: watermarkAward}).
+		RunWith(r.runner).
+		QueryRowContext(ctx).
+		Scan(&watermark)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.seed(ctx)
+		}
+		return nil, err
+	}
+
+	events, err := r.findPlayerEvents(ctx, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rank changes and bans: %w", err)
+	}
+
+	awards, err := r.findAwardEvents(ctx, watermark, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find awards: %w", err)
+	}
+
+	return append(events, awards...), nil
+}
+
+func (r *WatchRepository) MarkSeen(ctx context.Context, events []event.Event) error {
+	playerIDs := make([]uint32, 0, len(events))
+	var roundID uint32
+	for _, e := range events {
+		switch e.Type {
+		case event.TypeRankChanged, event.TypePlayerBanned:
+			playerIDs = append(playerIDs, e.Player.ID)
+		case event.TypeAwardEarned:
+			roundID = max(roundID, e.Award.RoundID)
+		}
+	}
+
+	tx, err := r.runner.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback is a noop if the transaction has already been committed
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if len(playerIDs) > 0 {
+		_, err = buildPlayerStateInsert(sq.Eq{columnID: playerIDs}).
+			Suffix(fmt.Sprintf(
+				"ON DUPLICATE KEY UPDATE %[1]s = VALUES(%[1]s), %[2]s = VALUES(%[2]s), %[3]s = VALUES(%[3]s)",
+				columnRankID,
+				columnTimesBanned,
+				columnPermanentBan,
+			)).
+			RunWith(tx).
+			ExecContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to update player states: %w", err)
+		}
+	}
+
+	if roundID != 0 {
+		_, err = sq.
+			Update(watermarkTable).
+			Set(columnValue, sq.Expr(fmt.Sprintf("GREATEST(%s, ?)", columnValue), roundID)).
+			Where(sq.Eq{columnName: watermarkAward}).
+			RunWith(tx).
+			ExecContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to update award watermark: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// seed Records the current state as seen
+func (r *WatchRepository) seed(ctx context.Context) error {
+	tx, err := r.runner.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback is a noop if the transaction has already been committed
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = buildPlayerStateInsert(nil).
+		Options("IGNORE").
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to seed player states: %w", err)
+	}
+
+	_, err = sq.
+		Insert(watermarkTable).
+		Columns(
+			columnName,
+			columnValue,
+		).
+		Select(sq.
+			Select(
+				fmt.Sprintf("'%s'", watermarkAward),
+				fmt.Sprintf("COALESCE(MAX(%s), 0)", columnRoundID),
+			).
+			From(awardRecordTable),
+		).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to seed award watermark: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *WatchRepository) findPlayerEvents(ctx context.Context, timestamp uint32) ([]event.Event, error) {
+	current := func(column string) string {
+		return sqlutil.Qualify(playerTable, column)
+	}
+	seen := func(column string) string {
+		return fmt.Sprintf("COALESCE(%s, 0)", sqlutil.Qualify(playerStateTable, column))
+	}
+
+	query := sq.
+		Select(
+			current(columnID),
+			current(columnName),
+			current(columnRankID),
+			current(columnRankChanged),
+			current(columnRankDecreased),
+			current(columnTimesBanned),
+			current(columnPermanentBan),
+			fmt.Sprintf("%s IS NOT NULL", sqlutil.Qualify(playerStateTable, columnPlayerID)),
+			seen(columnRankID),
+			seen(columnTimesBanned),
+			seen(columnPermanentBan),
+		).
+		From(playerTable).
+		LeftJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			playerStateTable,
+			sqlutil.Qualify(playerStateTable, columnPlayerID),
+			current(columnID),
+		)).
+		Where(sq.Or{
+			// Rank changes are only reported while flagged, since ranks may also be changed without being a promotion
+			sq.And{
+				sq.Or{
+					sq.Eq{current(columnRankChanged): 1},
+					sq.Eq{current(columnRankDecreased): 1},
+				},
+				sq.Or{
+					sq.Eq{sqlutil.Qualify(playerStateTable, columnPlayerID): nil},
+					sq.Expr(fmt.Sprintf("%s <> %s", current(columnRankID), seen(columnRankID))),
+				},
+			},
+			sq.Expr(fmt.Sprintf("%s > %s", current(columnTimesBanned), seen(columnTimesBanned))),
+			sq.Expr(fmt.Sprintf("%s > %s", current(columnPermanentBan), seen(columnPermanentBan))),
+		})
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]event.Event, 0)
+	for rows.Next() {
+		var p event.PlayerRef
+		var rankID, seenRankID uint8
+		var rankChanged, rankDecreased, permanent, seenPermanent, hasState bool
+		var banned, seenBanned uint16
+		if err = rows.Scan(
+			&p.ID,
+			&p.Name,
+			&rankID,
+			&rankChanged,
+			&rankDecreased,
+			&banned,
+			&permanent,
+			&hasState,
+			&seenRankID,
+			&seenBanned,
+			&seenPermanent,
+		); err != nil {
+			return nil, err
+		}
+
+		if (rankChanged || rankDecreased) && (!hasState || rankID != seenRankID) {
+			events = append(events, event.Event{
+				Type:      event.TypeRankChanged,
+				Player:    p,
+				Timestamp: timestamp,
+				Rank: &event.RankChange{
+					ID:        rankID,
+					Decreased: rankDecreased,
+				},
+			})
+		}
+
+		if banned > seenBanned || (permanent && !seenPermanent) {
+			events = append(events, event.Event{
+				Type:      event.TypePlayerBanned,
+				Player:    p,
+				Timestamp: timestamp,
+				Ban: &event.Ban{
+					TimesBanned: banned,
+					Permanent:   permanent,
+				},
+			})
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *WatchRepository) findAwardEvents(ctx context.Context, watermark uint32, timestamp uint32) ([]event.Event, error) {
+	query := sq.
+		Select(
+			sqlutil.Qualify(awardRecordTable, columnPlayerID),
+			sqlutil.Qualify(playerTable, columnName),
+			sqlutil.Qualify(awardRecordTable, columnAwardID),
+			sqlutil.Qualify(awardRecordTable, columnLevel),
+			sqlutil.Qualify(awardRecordTable, columnRoundID),
+		).
+		From(awardRecordTable).
+		InnerJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			playerTable,
+			sqlutil.Qualify(awardRecordTable, columnPlayerID),
+			sqlutil.Qualify(playerTable, columnID),
+		)).
+		Where(sq.Gt{sqlutil.Qualify(awardRecordTable, columnRoundID): watermark}).
+		OrderBy(fmt.Sprintf("%s ASC", sqlutil.Qualify(awardRecordTable, columnRoundID)))
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]event.Event, 0)
+	for rows.Next() {
+		e := event.Event{
+			Type:      event.TypeAwardEarned,
+			Timestamp: timestamp,
+			Award:     &event.AwardEarned{},
+		}
+		if err = rows.Scan(
+			&e.Player.ID,
+			&e.Player.Name,
+			&e.Award.ID,
+			&e.Award.Level,
+			&e.Award.RoundID,
+		); err != nil {
+			return nil, err
+		}
+
+		events = append(events, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// buildPlayerStateInsert Builds a query recording the current state of the players matching pred (all if nil)
+func buildPlayerStateInsert(pred any) sq.InsertBuilder {
+	current := sq.
+		Select(
+			columnID,
+			columnRankID,
+			columnTimesBanned,
+			columnPermanentBan,
+		).
+		From(playerTable)
+	if pred != nil {
+		current = current.Where(pred)
+	}
+
+	return sq.
+		Insert(playerStateTable).
+		Columns(
+			columnPlayerID,
+			columnRankID,
+			columnTimesBanned,
+			columnPermanentBan,
+		).
+		Select(current)
+}
@@ -36,42 +36,6 @@ const (
 	OperationBluePearl uint16 = 603
 )
 
-var (
-	IDs = []uint16{
-		KubraDam,
-		MashtuurCity,
-		OperationCleanSweep,
-		ZatarWetlands,
-		StrikeAtKarkand,
-		SharqiPeninsula,
-		GulfOfOman,
-		OperationSmokescreen,
-		TarabaQuarry,
-		RoadToJalalabad,
-		DaqingOilfields,
-		DalianPlant,
-		DragonValley,
-		FushePass,
-		HinganHills,
-		SonghuaStalemate,
-		GreatWall,
-		MidnightSun,
-		OperationRoadRage,
-		OperationHarvest,
-		DevilsPerch,
-		IronGator,
-		NightFlight,
-		Warlord,
-		Leviathan,
-		MassDestruction,
-		Surge,
-		GhostTown,
-		WakeIsland2007,
-		HighwayTampa,
-		OperationBluePearl,
-	}
-)
-
 // Record Only used fields are modeled
 type Record struct {
 	Player PlayerRef
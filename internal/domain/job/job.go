@@ -0,0 +1,13 @@
+package job
+
+// State Execution state of a scheduled job
+type State struct {
+	Name string
+	// LastRun Start of the last run, zero if the job never ran
+	LastRun uint32
+	// LastDuration Duration of the last run in milliseconds
+	LastDuration uint32
+	// LastError Error returned by the last run, empty if the run succeeded
+	LastError string
+	NextRun   uint32
+}
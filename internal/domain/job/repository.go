@@ -0,0 +1,11 @@
+package job
+
+import (
+	"context"
+)
+
+type Repository interface {
+	FindAll(ctx context.Context) ([]State, error)
+	// Save Inserts or updates the given state (identified by name)
+	Save(ctx context.Context, state State) error
+}
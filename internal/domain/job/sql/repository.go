@@ -0,0 +1,103 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/cetteup/gasp/internal/domain/job"
+)
+
+const (
+	jobTable = "job"
+
+	columnName         = "name"
+	columnLastRun      = "last_run"
+	columnLastDuration = "last_duration"
+	columnLastError    = "last_error"
+	columnNextRun      = "next_run"
+)
+
+type Repository struct {
+	runner sq.BaseRunner
+}
+
+func NewRepository(runner sq.BaseRunner) *Repository {
+	return &Repository{
+		runner: runner,
+	}
+}
+
+func (r *Repository) FindAll(ctx context.Context) ([]job.State, error) {
+	query := sq.
+		Select(
+			columnName,
+			columnLastRun,
+			columnLastDuration,
+			columnLastError,
+			columnNextRun,
+		).
+		From(jobTable).
+		OrderBy(fmt.Sprintf("%s ASC", columnName))
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]job.State, 0)
+	for rows.Next() {
+		var state job.State
+		if err = rows.Scan(
+			&state.Name,
+			&state.LastRun,
+			&state.LastDuration,
+			&state.LastError,
+			&state.NextRun,
+		); err != nil {
+			return nil, err
+		}
+
+		states = append(states, state)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}
+
+func (r *Repository) Save(ctx context.Context, state job.State) error {
+	query := sq.
+		Insert(jobTable).
+		Columns(
+			columnName,
+			columnLastRun,
+			columnLastDuration,
+			columnLastError,
+			columnNextRun,
+		).
+		Values(
+			state.Name,
+			state.LastRun,
+			state.LastDuration,
+			state.LastError,
+			state.NextRun,
+		).
+		Suffix(fmt.Sprintf(
+			"ON DUPLICATE KEY UPDATE %[1]s = VALUES(%[1]s), %[2]s = VALUES(%[2]s), %[3]s = VALUES(%[3]s), %[4]s = VALUES(%[4]s)",
+			columnLastRun,
+			columnLastDuration,
+			columnLastError,
+			columnNextRun,
+		))
+
+	_, err := query.RunWith(r.runner).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
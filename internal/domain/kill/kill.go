@@ -16,6 +16,15 @@ type HistoryRecord struct {
 	RelationType RelationType
 }
 
+// HeadToHead Kills between two players.
+// Read as [player] killed [other] [kills] times, [other] killed [player] [deaths] times.
+type HeadToHead struct {
+	Player PlayerRef
+	Other  PlayerRef
+	Kills  uint16
+	Deaths uint16
+}
+
 type PlayerStub struct {
 	ID     uint32
 	Name   string
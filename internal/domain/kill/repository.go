@@ -6,4 +6,8 @@ import (
 
 type HistoryRecordRepository interface {
 	FindTopRelatedByPlayerID(ctx context.Context, playerID uint32) ([]HistoryRecord, error)
+	// FindRelatedByPlayerID Returns a page of the players related to the player, ordered by kills (descending), and the
+	// total number of related players
+	FindRelatedByPlayerID(ctx context.Context, playerID uint32, relationType RelationType, offset, limit uint32) ([]HistoryRecord, int, error)
+	FindHeadToHead(ctx context.Context, playerID, otherID uint32) (HeadToHead, error)
 }
@@ -123,3 +123,107 @@ func (r *HistoryRecordRepository) FindTopRelatedByPlayerID(ctx context.Context,
 
 	return records, nil
 }
+
+func (r *HistoryRecordRepository) FindRelatedByPlayerID(ctx context.Context, playerID uint32, relationType kill.RelationType, offset, limit uint32) ([]kill.HistoryRecord, int, error) {
+	// Read as [other] is [relation type] of [player]
+	var playerColumn, otherColumn string
+	switch relationType {
+	case kill.RelationTypeVictim:
+		playerColumn, otherColumn = columnAttacker, columnVictim
+	case kill.RelationTypeAttacker:
+		playerColumn, otherColumn = columnVictim, columnAttacker
+	default:
+		return nil, 0, fmt.Errorf("unknown relation type: %d", relationType)
+	}
+
+	count := sq.
+		Select("COUNT(*)").
+		From(killHistoryRecordTable).
+		Where(sq.Eq{playerColumn: playerID})
+
+	var total int
+	if err := count.RunWith(r.runner).QueryRowContext(ctx).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := sq.
+		Select(
+			sqlutil.Qualify(killHistoryRecordTable, playerColumn),
+			sqlutil.Qualify(killHistoryRecordTable, otherColumn),
+			sqlutil.Qualify(playerTable, columnName),
+			sqlutil.Qualify(playerTable, columnRankID),
+			sqlutil.Qualify(killHistoryRecordTable, columnKills),
+		).
+		From(killHistoryRecordTable).
+		InnerJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			playerTable,
+			sqlutil.Qualify(killHistoryRecordTable, otherColumn),
+			sqlutil.Qualify(playerTable, columnID),
+		)).
+		Where(sq.Eq{sqlutil.Qualify(killHistoryRecordTable, playerColumn): playerID}).
+		OrderBy(
+			fmt.Sprintf("%s DESC", sqlutil.Qualify(killHistoryRecordTable, columnKills)),
+			fmt.Sprintf("%s ASC", sqlutil.Qualify(playerTable, columnName)),
+		).
+		Offset(uint64(offset)).
+		Limit(uint64(limit))
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	records := make([]kill.HistoryRecord, 0, limit)
+	for rows.Next() {
+		record := kill.HistoryRecord{
+			RelationType: relationType,
+		}
+		if err = rows.Scan(
+			&record.Player.ID,
+			&record.Other.ID,
+			&record.Other.Name,
+			&record.Other.RankID,
+			&record.Kills,
+		); err != nil {
+			return nil, 0, err
+		}
+
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return records, total, nil
+}
+
+func (r *HistoryRecordRepository) FindHeadToHead(ctx context.Context, playerID, otherID uint32) (kill.HeadToHead, error) {
+	sumKillsBy := func(attackerID uint32) sq.Sqlizer {
+		return sq.Expr(
+			fmt.Sprintf("COALESCE(SUM(CASE WHEN %s = ? THEN %s ELSE 0 END), 0)", columnAttacker, columnKills),
+			attackerID,
+		)
+	}
+
+	query := sq.
+		Select().
+		Column(sumKillsBy(playerID)).
+		Column(sumKillsBy(otherID)).
+		From(killHistoryRecordTable).
+		Where(sq.Or{
+			sq.Eq{columnAttacker: playerID, columnVictim: otherID},
+			sq.Eq{columnAttacker: otherID, columnVictim: playerID},
+		})
+
+	h2h := kill.HeadToHead{
+		Player: kill.PlayerRef{ID: playerID},
+		Other:  kill.PlayerRef{ID: otherID},
+	}
+	if err := query.RunWith(r.runner).QueryRowContext(ctx).Scan(&h2h.Kills, &h2h.Deaths); err != nil {
+		return kill.HeadToHead{}, err
+	}
+
+	return h2h, nil
+}
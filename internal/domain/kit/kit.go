@@ -10,18 +10,6 @@ const (
 	Sniper   uint8 = 6
 )
 
-var (
-	IDs = []uint8{
-		AntiTank,
-		Assault,
-		Engineer,
-		Medic,
-		SpecOps,
-		Support,
-		Sniper,
-	}
-)
-
 type Record struct {
 	Player PlayerRef
 	Kit    KitRef
@@ -0,0 +1,137 @@
+package cached
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cetteup/gasp/internal/cache"
+	"github.com/cetteup/gasp/internal/domain/leaderboard"
+	"github.com/cetteup/gasp/internal/domain/season"
+)
+
+const (
+	keyPrefix = "leaderboard:"
+)
+
+// Repository Caches results of the wrapped leaderboard.Repository
+type Repository struct {
+	repository leaderboard.Repository
+	store      cache.Store
+}
+
+func NewRepository(repository leaderboard.Repository, store cache.Store) *Repository {
+	return &Repository{
+		repository: repository,
+		store:      store,
+	}
+}
+
+type result[T any] struct {
+	Entries []leaderboard.Entry[T]
+	Size    int
+}
+
+func (r *Repository) FindTopPlayersByScore(ctx context.Context, scoreType leaderboard.ScoreType, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.PlayerStub], int, error) {
+	return find(ctx, r.store, buildKey(ctx, "score", scoreType, filter), func(ctx context.Context) ([]leaderboard.Entry[leaderboard.PlayerStub], int, error) {
+		return r.repository.FindTopPlayersByScore(ctx, scoreType, filter)
+	})
+}
+
+func (r *Repository) FindTopPlayersByKit(ctx context.Context, kitID uint8, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.KitRecord], int, error) {
+	return find(ctx, r.store, buildKey(ctx, "kit", kitID, filter), func(ctx context.Context) ([]leaderboard.Entry[leaderboard.KitRecord], int, error) {
+		return r.repository.FindTopPlayersByKit(ctx, kitID, filter)
+	})
+}
+
+func (r *Repository) FindTopPlayersByVehicle(ctx context.Context, vehicleID uint8, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.VehicleRecord], int, error) {
+	return find(ctx, r.store, buildKey(ctx, "vehicle", vehicleID, filter), func(ctx context.Context) ([]leaderboard.Entry[leaderboard.VehicleRecord], int, error) {
+		return r.repository.FindTopPlayersByVehicle(ctx, vehicleID, filter)
+	})
+}
+
+func (r *Repository) FindTopPlayersByWeapon(ctx context.Context, weaponID uint8, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.WeaponRecord], int, error) {
+	return find(ctx, r.store, buildKey(ctx, "weapon", weaponID, filter), func(ctx context.Context) ([]leaderboard.Entry[leaderboard.WeaponRecord], int, error) {
+		return r.repository.FindTopPlayersByWeapon(ctx, weaponID, filter)
+	})
+}
+
+func (r *Repository) FindTopPlayersByArmy(ctx context.Context, armyID uint8, by leaderboard.ArmyRankBy, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.ArmyRecord], int, error) {
+	return find(ctx, r.store, buildKey(ctx, "army", fmt.Sprintf("%d:%d", armyID, by), filter), func(ctx context.Context) ([]leaderboard.Entry[leaderboard.ArmyRecord], int, error) {
+		return r.repository.FindTopPlayersByArmy(ctx, armyID, by, filter)
+	})
+}
+
+func (r *Repository) FindTopPlayersByField(ctx context.Context, fieldID uint16, by leaderboard.FieldRankBy, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.FieldRecord], int, error) {
+	return find(ctx, r.store, buildKey(ctx, "field", fmt.Sprintf("%d:%d", fieldID, by), filter), func(ctx context.Context) ([]leaderboard.Entry[leaderboard.FieldRecord], int, error) {
+		return r.repository.FindTopPlayersByField(ctx, fieldID, by, filter)
+	})
+}
+
+func (r *Repository) FindRisingStars(ctx context.Context, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.RisingStar], int, error) {
+	return find(ctx, r.store, buildKey(ctx, "risingstar", 0, filter), func(ctx context.Context) ([]leaderboard.Entry[leaderboard.RisingStar], int, error) {
+		return r.repository.FindRisingStars(ctx, filter)
+	})
+}
+
+func (r *Repository) GetRisingStarUpdateTimestamp(ctx context.Context) (uint32, error) {
+	key := keyPrefix + "risingstar:timestamp"
+	if s, ok := season.FromContext(ctx); ok {
+		key += fmt.Sprintf(":season:%d", s)
+	}
+	return cache.GetOrLoad(ctx, r.store, key, r.repository.GetRisingStarUpdateTimestamp)
+}
+
+// GetUpdateTimestamp Not cached, since cached leaderboards may have been loaded at different points in time anyway
+func (r *Repository) GetUpdateTimestamp(ctx context.Context, filter leaderboard.Filter) (uint32, error) {
+	return r.repository.GetUpdateTimestamp(ctx, filter)
+}
+
+// InvalidatePlayer Drops all cached leaderboards, since any change to a player's data may shift every position
+func (r *Repository) InvalidatePlayer(ctx context.Context, _ uint32) error {
+	return r.store.DeletePrefix(ctx, keyPrefix)
+}
+
+// InvalidateStats Drops all cached leaderboards
+func (r *Repository) InvalidateStats(ctx context.Context) error {
+	return r.store.DeletePrefix(ctx, keyPrefix)
+}
+
+func find[T any](
+	ctx context.Context,
+	store cache.Store,
+	key string,
+	load func(ctx context.Context) ([]leaderboard.Entry[T], int, error),
+) ([]leaderboard.Entry[T], int, error) {
+	res, err := cache.GetOrLoad(ctx, store, key, func(ctx context.Context) (result[T], error) {
+		entries, size, err := load(ctx)
+		if err != nil {
+			return result[T]{}, err
+		}
+		return result[T]{Entries: entries, Size: size}, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res.Entries, res.Size, nil
+}
+
+func buildKey[T any](ctx context.Context, t string, id T, filter leaderboard.Filter) string {
+	key := fmt.Sprintf("%s%s:%v:", keyPrefix, t, id)
+	if s, ok := season.FromContext(ctx); ok {
+		key += fmt.Sprintf("season:%d:", s)
+	}
+	if filter.Country != "" {
+		// Country codes are case-insensitive
+		key += fmt.Sprintf("country:%s:", strings.ToLower(filter.Country))
+	}
+	if filter.Since != 0 {
+		key += fmt.Sprintf("since:%d:", filter.Since)
+	}
+
+	if filter.PID != nil {
+		return fmt.Sprintf("%spid:%d", key, *filter.PID)
+	}
+	return fmt.Sprintf("%spos:%d-%d", key, filter.First, filter.Last)
+}
@@ -1,9 +1,52 @@
 package leaderboard
 
+import (
+	"time"
+)
+
 type Filter struct {
 	PID   *uint32
 	First uint32
 	Last  uint32
+	// Country Optional (case-insensitive) country code to restrict the leaderboard to, positions are computed within
+	// the country
+	Country string
+	// Since Optional timestamp to only consider rounds ended since, rather than lifetime totals (only supported by
+	// score, kit, vehicle and weapon leaderboards)
+	Since uint32
+}
+
+// Window Period of time to aggregate leaderboards over, always starting at the beginning of the current
+// (calendar) period in UTC
+type Window int
+
+const (
+	WindowLifetime Window = iota
+	WindowDay
+	WindowWeek
+	WindowMonth
+)
+
+// Since Returns the start of the window containing now, or zero for lifetime windows
+func (w Window) Since(now time.Time) uint32 {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var start time.Time
+	switch w {
+	case WindowDay:
+		start = day
+	case WindowWeek:
+		// Weeks start on Monday (ISO 8601)
+		start = day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case WindowMonth:
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return 0
+	}
+
+	// Will overflow on 7 February 2106 at 06:28:15 UTC
+	return uint32(start.Unix())
 }
 
 func NewPIDFilter(pid uint32) Filter {
@@ -86,6 +129,31 @@ type WeaponRef struct {
 	ID uint8
 }
 
+type ArmyRecord struct {
+	Player PlayerStub
+	Army   ArmyRef
+	Time   uint32
+	Wins   uint16
+	Losses uint16
+	Score  int
+}
+
+type ArmyRef struct {
+	ID uint8
+}
+
+type FieldRecord struct {
+	Player PlayerStub
+	Field  FieldRef
+	Time   uint32
+	Wins   uint16
+	Losses uint16
+}
+
+type FieldRef struct {
+	ID uint16
+}
+
 type RisingStar struct {
 	Player      PlayerStub
 	WeeklyScore uint32
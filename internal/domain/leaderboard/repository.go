@@ -13,11 +13,37 @@ const (
 	ScoreTypeCombat
 )
 
+// ArmyRankBy Army leaderboards can be ranked by different stats
+type ArmyRankBy int
+
+const (
+	ArmyRankByScore ArmyRankBy = iota
+	ArmyRankByWins
+)
+
+// FieldRankBy Field (map) leaderboards can be ranked by different stats
+type FieldRankBy int
+
+const (
+	FieldRankByWins FieldRankBy = iota
+	FieldRankByTime
+)
+
 type Repository interface {
 	FindTopPlayersByScore(ctx context.Context, scoreType ScoreType, filter Filter) ([]Entry[PlayerStub], int, error)
 	FindTopPlayersByKit(ctx context.Context, kitID uint8, filter Filter) ([]Entry[KitRecord], int, error)
 	FindTopPlayersByVehicle(ctx context.Context, vehicleID uint8, filter Filter) ([]Entry[VehicleRecord], int, error)
 	FindTopPlayersByWeapon(ctx context.Context, weaponID uint8, filter Filter) ([]Entry[WeaponRecord], int, error)
+	FindTopPlayersByArmy(ctx context.Context, armyID uint8, by ArmyRankBy, filter Filter) ([]Entry[ArmyRecord], int, error)
+	FindTopPlayersByField(ctx context.Context, fieldID uint16, by FieldRankBy, filter Filter) ([]Entry[FieldRecord], int, error)
 	FindRisingStars(ctx context.Context, filter Filter) ([]Entry[RisingStar], int, error)
 	GetRisingStarUpdateTimestamp(ctx context.Context) (uint32, error)
+	// GetUpdateTimestamp Returns the point in time the (non rising star) leaderboards matching the filter reflect
+	GetUpdateTimestamp(ctx context.Context, filter Filter) (uint32, error)
+}
+
+type RisingStarRepository interface {
+	// Rebuild Replaces all rising stars with the players who gained the most score in rounds ended since the given
+	// timestamp, recording timestamp as the time of the update
+	Rebuild(ctx context.Context, since uint32, timestamp uint32) error
 }
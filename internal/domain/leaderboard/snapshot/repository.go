@@ -0,0 +1,274 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/cetteup/gasp/internal/catalogue"
+	"github.com/cetteup/gasp/internal/domain/leaderboard"
+	"github.com/cetteup/gasp/internal/domain/season"
+)
+
+const (
+	// Same limit as applied by the sql repository
+	maxResults = 10000
+)
+
+var (
+	scoreTypes = []leaderboard.ScoreType{
+		leaderboard.ScoreTypeOverall,
+		leaderboard.ScoreTypeCommand,
+		leaderboard.ScoreTypeTeam,
+		leaderboard.ScoreTypeCombat,
+	}
+	armyRankBys = []leaderboard.ArmyRankBy{
+		leaderboard.ArmyRankByScore,
+		leaderboard.ArmyRankByWins,
+	}
+	fieldRankBys = []leaderboard.FieldRankBy{
+		leaderboard.FieldRankByWins,
+		leaderboard.FieldRankByTime,
+	}
+)
+
+// Repository Serves leaderboards from periodically materialised in-memory snapshots of the wrapped repository.
+// Until the first snapshot has been taken, all calls are passed through to the wrapped repository. Same goes for calls
+// for national, time-windowed or archived season leaderboards.
+type Repository struct {
+	repository leaderboard.Repository
+	catalogue  *catalogue.Catalogue
+	current    atomic.Pointer[snapshot]
+}
+
+type snapshot struct {
+	asOf     uint32
+	score    map[leaderboard.ScoreType]*board[leaderboard.PlayerStub]
+	kits     map[uint8]*board[leaderboard.KitRecord]
+	vehicles map[uint8]*board[leaderboard.VehicleRecord]
+	weapons  map[uint8]*board[leaderboard.WeaponRecord]
+	armies   map[armyKey]*board[leaderboard.ArmyRecord]
+	fields   map[fieldKey]*board[leaderboard.FieldRecord]
+}
+
+type armyKey struct {
+	id uint8
+	by leaderboard.ArmyRankBy
+}
+
+type fieldKey struct {
+	id uint16
+	by leaderboard.FieldRankBy
+}
+
+type board[T any] struct {
+	entries []leaderboard.Entry[T]
+	// index Maps player ids to their entry's index
+	index map[uint32]int
+}
+
+// NewRepository Returns a repository materialising the leaderboards of every army, field, kit, vehicle and weapon known
+// to the catalogue
+func NewRepository(repository leaderboard.Repository, catalogue *catalogue.Catalogue) *Repository {
+	return &Repository{
+		repository: repository,
+		catalogue:  catalogue,
+	}
+}
+
+func (r *Repository) FindTopPlayersByScore(ctx context.Context, scoreType leaderboard.ScoreType, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.PlayerStub], int, error) {
+	s := r.load(ctx, filter)
+	if s == nil {
+		return r.repository.FindTopPlayersByScore(ctx, scoreType, filter)
+	}
+	return lookup(s.score, scoreType, filter)
+}
+
+func (r *Repository) FindTopPlayersByKit(ctx context.Context, kitID uint8, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.KitRecord], int, error) {
+	s := r.load(ctx, filter)
+	if s == nil {
+		return r.repository.FindTopPlayersByKit(ctx, kitID, filter)
+	}
+	return lookup(s.kits, kitID, filter)
+}
+
+func (r *Repository) FindTopPlayersByVehicle(ctx context.Context, vehicleID uint8, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.VehicleRecord], int, error) {
+	s := r.load(ctx, filter)
+	if s == nil {
+		return r.repository.FindTopPlayersByVehicle(ctx, vehicleID, filter)
+	}
+	return lookup(s.vehicles, vehicleID, filter)
+}
+
+func (r *Repository) FindTopPlayersByWeapon(ctx context.Context, weaponID uint8, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.WeaponRecord], int, error) {
+	s := r.load(ctx, filter)
+	if s == nil {
+		return r.repository.FindTopPlayersByWeapon(ctx, weaponID, filter)
+	}
+	return lookup(s.weapons, weaponID, filter)
+}
+
+func (r *Repository) FindTopPlayersByArmy(ctx context.Context, armyID uint8, by leaderboard.ArmyRankBy, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.ArmyRecord], int, error) {
+	s := r.load(ctx, filter)
+	if s == nil {
+		return r.repository.FindTopPlayersByArmy(ctx, armyID, by, filter)
+	}
+	return lookup(s.armies, armyKey{id: armyID, by: by}, filter)
+}
+
+func (r *Repository) FindTopPlayersByField(ctx context.Context, fieldID uint16, by leaderboard.FieldRankBy, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.FieldRecord], int, error) {
+	s := r.load(ctx, filter)
+	if s == nil {
+		return r.repository.FindTopPlayersByField(ctx, fieldID, by, filter)
+	}
+	return lookup(s.fields, fieldKey{id: fieldID, by: by}, filter)
+}
+
+// FindRisingStars Rising stars are already materialised in their own table, so there is no need to snapshot them
+func (r *Repository) FindRisingStars(ctx context.Context, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.RisingStar], int, error) {
+	return r.repository.FindRisingStars(ctx, filter)
+}
+
+func (r *Repository) GetRisingStarUpdateTimestamp(ctx context.Context) (uint32, error) {
+	return r.repository.GetRisingStarUpdateTimestamp(ctx)
+}
+
+// GetUpdateTimestamp Returns the snapshot's point in time, unless the filtered leaderboard is not served from the snapshot
+func (r *Repository) GetUpdateTimestamp(ctx context.Context, filter leaderboard.Filter) (uint32, error) {
+	s := r.load(ctx, filter)
+	if s == nil {
+		return r.repository.GetUpdateTimestamp(ctx, filter)
+	}
+	return s.asOf, nil
+}
+
+// load Returns the current snapshot, unless it cannot be used to serve the filtered leaderboard
+func (r *Repository) load(ctx context.Context, filter leaderboard.Filter) *snapshot {
+	// Snapshots only contain live lifetime totals
+	if _, ok := season.FromContext(ctx); ok || filter.Since != 0 {
+		return nil
+	}
+	// Snapshots only contain the global top entries, which would not include every player of a country
+	if filter.Country != "" {
+		return nil
+	}
+	return r.current.Load()
+}
+
+// Refresh Materialises all leaderboards and atomically swaps the current snapshot for the new one
+func (r *Repository) Refresh(ctx context.Context) error {
+	previous := r.current.Load()
+	filter := leaderboard.NewPositionFilter(0, maxResults)
+	s := &snapshot{
+		// Will overflow on 7 February 2106 at 06:28:15 UTC
+		asOf:     uint32(time.Now().UTC().Unix()),
+		score:    make(map[leaderboard.ScoreType]*board[leaderboard.PlayerStub], len(scoreTypes)),
+		kits:     make(map[uint8]*board[leaderboard.KitRecord], len(r.catalogue.KitIDs())),
+		vehicles: make(map[uint8]*board[leaderboard.VehicleRecord], len(r.catalogue.VehicleIDs())),
+		weapons:  make(map[uint8]*board[leaderboard.WeaponRecord], len(r.catalogue.WeaponIDs())),
+		armies:   make(map[armyKey]*board[leaderboard.ArmyRecord], len(r.catalogue.ArmyIDs())*len(armyRankBys)),
+		fields:   make(map[fieldKey]*board[leaderboard.FieldRecord], len(r.catalogue.FieldIDs())*len(fieldRankBys)),
+	}
+
+	for _, scoreType := range scoreTypes {
+		entries, _, err := r.repository.FindTopPlayersByScore(ctx, scoreType, filter)
+		if err != nil {
+			return fmt.Errorf("failed to find top players by score %d: %w", scoreType, err)
+		}
+		s.score[scoreType] = newBoard(entries, func(data leaderboard.PlayerStub) uint32 { return data.ID })
+	}
+
+	for _, id := range r.catalogue.KitIDs() {
+		entries, _, err := r.repository.FindTopPlayersByKit(ctx, id, filter)
+		if err != nil {
+			return fmt.Errorf("failed to find top players by kit %d: %w", id, err)
+		}
+		s.kits[id] = newBoard(entries, func(data leaderboard.KitRecord) uint32 { return data.Player.ID })
+	}
+
+	for _, id := range r.catalogue.VehicleIDs() {
+		entries, _, err := r.repository.FindTopPlayersByVehicle(ctx, id, filter)
+		if err != nil {
+			return fmt.Errorf("failed to find top players by vehicle %d: %w", id, err)
+		}
+		s.vehicles[id] = newBoard(entries, func(data leaderboard.VehicleRecord) uint32 { return data.Player.ID })
+	}
+
+	for _, id := range r.catalogue.WeaponIDs() {
+		entries, _, err := r.repository.FindTopPlayersByWeapon(ctx, id, filter)
+		if err != nil {
+			return fmt.Errorf("failed to find top players by weapon %d: %w", id, err)
+		}
+		s.weapons[id] = newBoard(entries, func(data leaderboard.WeaponRecord) uint32 { return data.Player.ID })
+	}
+
+	for _, id := range r.catalogue.ArmyIDs() {
+		for _, by := range armyRankBys {
+			entries, _, err := r.repository.FindTopPlayersByArmy(ctx, id, by, filter)
+			if err != nil {
+				return fmt.Errorf("failed to find top players by army %d (%d): %w", id, by, err)
+			}
+			s.armies[armyKey{id: id, by: by}] = newBoard(entries, func(data leaderboard.ArmyRecord) uint32 { return data.Player.ID })
+		}
+	}
+
+	for _, id := range r.catalogue.FieldIDs() {
+		for _, by := range fieldRankBys {
+			entries, _, err := r.repository.FindTopPlayersByField(ctx, id, by, filter)
+			if err != nil {
+				return fmt.Errorf("failed to find top players by field %d (%d): %w", id, by, err)
+			}
+			s.fields[fieldKey{id: id, by: by}] = newBoard(entries, func(data leaderboard.FieldRecord) uint32 { return data.Player.ID })
+		}
+	}
+
+	// Discard the snapshot if the current one was dropped while taking it, since it may contain stats from before the reset
+	if !r.current.CompareAndSwap(previous, s) {
+		return fmt.Errorf("snapshot was invalidated while being taken")
+	}
+
+	return nil
+}
+
+// InvalidateStats Drops the current snapshot and takes a new one. Calls are passed through to the wrapped repository
+// until the new snapshot has been taken.
+func (r *Repository) InvalidateStats(ctx context.Context) error {
+	r.current.Store(nil)
+	return r.Refresh(ctx)
+}
+
+func newBoard[T any](entries []leaderboard.Entry[T], pid func(data T) uint32) *board[T] {
+	b := &board[T]{
+		entries: entries,
+		index:   make(map[uint32]int, len(entries)),
+	}
+	for i, entry := range entries {
+		b.index[pid(entry.Data)] = i
+	}
+	return b
+}
+
+func lookup[K comparable, T any](boards map[K]*board[T], id K, filter leaderboard.Filter) ([]leaderboard.Entry[T], int, error) {
+	b, ok := boards[id]
+	if !ok {
+		// Same as an empty leaderboard in the sql repository
+		return []leaderboard.Entry[T]{}, 0, nil
+	}
+
+	if filter.PID != nil {
+		i, ok2 := b.index[*filter.PID]
+		if !ok2 {
+			return []leaderboard.Entry[T]{}, len(b.entries), nil
+		}
+		return []leaderboard.Entry[T]{b.entries[i]}, len(b.entries), nil
+	}
+
+	first := min(int(filter.First), len(b.entries))
+	last := min(int(filter.Last), len(b.entries))
+	// Return a copy to not hand out references to the snapshot
+	entries := make([]leaderboard.Entry[T], last-first)
+	copy(entries, b.entries[first:last])
+
+	return entries, len(b.entries), nil
+}
@@ -0,0 +1,72 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cetteup/gasp/internal/domain/leaderboard"
+	"github.com/cetteup/gasp/internal/domain/season"
+)
+
+const (
+	snapshotAsOf = 1700000000
+	wrappedAsOf  = 1700000100
+)
+
+// wrappedRepository Only implements the update timestamp, any other call panics
+type wrappedRepository struct {
+	leaderboard.Repository
+}
+
+func (r wrappedRepository) GetUpdateTimestamp(_ context.Context, _ leaderboard.Filter) (uint32, error) {
+	return wrappedAsOf, nil
+}
+
+func TestRepository_load(t *testing.T) {
+	pid := uint32(42)
+	tests := []struct {
+		name string
+		// season Archived season the leaderboard is requested for, zero for the live stats
+		season       uint32
+		filter       leaderboard.Filter
+		noSnapshot   bool
+		wantSnapshot bool
+	}{
+		{name: "positions", filter: leaderboard.NewPositionFilter(0, 10), wantSnapshot: true},
+		{name: "player", filter: leaderboard.NewPIDFilter(pid), wantSnapshot: true},
+		{name: "before first snapshot", filter: leaderboard.NewPositionFilter(0, 10), noSnapshot: true},
+		{name: "country", filter: leaderboard.Filter{First: 0, Last: 10, Country: "de"}},
+		{name: "time window", filter: leaderboard.Filter{First: 0, Last: 10, Since: 1699990000}},
+		{name: "archived season", season: 3, filter: leaderboard.NewPositionFilter(0, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(wrappedRepository{}, nil)
+			if !tt.noSnapshot {
+				r.current.Store(&snapshot{asOf: snapshotAsOf})
+			}
+
+			ctx := context.Background()
+			if tt.season != 0 {
+				ctx = season.NewContext(ctx, tt.season)
+			}
+
+			if got := r.load(ctx, tt.filter); (got != nil) != tt.wantSnapshot {
+				t.Errorf("expected snapshot to be used: %t, got %t", tt.wantSnapshot, got != nil)
+			}
+
+			// Timestamp needs to match the source of the leaderboard
+			want := uint32(wrappedAsOf)
+			if tt.wantSnapshot {
+				want = snapshotAsOf
+			}
+			got, err := r.GetUpdateTimestamp(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != want {
+				t.Errorf("expected update timestamp %d, got %d", want, got)
+			}
+		})
+	}
+}
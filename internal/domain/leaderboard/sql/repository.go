@@ -2,9 +2,12 @@ package sql
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog/log"
 
 	"github.com/cetteup/gasp/internal/domain/leaderboard"
 	"github.com/cetteup/gasp/internal/sqlutil"
@@ -15,7 +18,17 @@ const (
 	kitRecordTable     = "player_kit"
 	vehicleRecordTable = "player_vehicle"
 	weaponRecordTable  = "player_weapon"
+	armyRecordTable    = "player_army"
+	fieldRecordTable   = "player_map"
 	risingStarTable    = "risingstar"
+	// risingStarUpdateTable Records every rebuild of the rising star table
+	risingStarUpdateTable = "risingstar_update"
+	roundTable            = "round"
+	// Per-round records are written alongside the lifetime records when processing round snapshots
+	playerRoundTable        = "player_round"
+	kitRoundRecordTable     = "player_round_kit"
+	vehicleRoundRecordTable = "player_round_vehicle"
+	weaponRoundRecordTable  = "player_round_weapon"
 
 	columnID           = "id"
 	columnName         = "name"
@@ -41,13 +54,26 @@ const (
 	columnShotsHit      = "hits"
 	columnTimesDeployed = "deployed"
 
+	columnArmyID = "army_id"
+	columnWins   = "wins"
+	columnLosses = "losses"
+
+	columnFieldID = "map_id"
+
 	columnPosition    = "pos"
 	columnPlayerID    = "player_id"
 	columnWeeklyScore = "weeklyscore"
 
+	columnRoundID   = "round_id"
+	columnEnd       = "time_end"
+	columnTimestamp = "timestamp"
+
 	virtualColumnPosition = "position"
 
 	maxResults = 10000
+
+	// weeklyScoreScale Weekly scores are stored as fixed-point values with four decimal places
+	weeklyScoreScale = 10000
 )
 
 type Repository struct {
@@ -89,6 +115,14 @@ func (r *Repository) FindTopPlayersByScore(ctx context.Context, scoreType leader
 		Where(sq.Gt{scoreColumn: 0}).
 		Limit(maxResults)
 
+	// Aggregate per-round records rather than using lifetime totals if a window was requested
+	if filter.Since != 0 {
+		cte = buildWindowedScoreCTE(scoreColumn, filter.Since)
+	}
+
+	// Country needs to be filtered within the CTE, so that positions are computed within the country
+	cte = addCountryFilter(cte, sqlutil.Qualify(playerTable, columnCountry), filter)
+
 	count, err := r.getEntryCount(ctx, cte)
 	if err != nil {
 		return nil, 0, err
@@ -174,6 +208,12 @@ func (r *Repository) FindTopPlayersByKit(ctx context.Context, kitID uint8, filte
 		}).
 		Limit(maxResults)
 
+	if filter.Since != 0 {
+		cte = buildWindowedRecordCTE(kitRoundRecordTable, kitRecordTable, columnKitID, kitID, filter.Since, columnTime, columnScore, columnKills, columnDeaths)
+	}
+
+	cte = addCountryFilter(cte, sqlutil.Qualify(playerTable, columnCountry), filter)
+
 	count, err := r.getEntryCount(ctx, cte)
 	if err != nil {
 		return nil, 0, err
@@ -265,6 +305,12 @@ func (r *Repository) FindTopPlayersByVehicle(ctx context.Context, vehicleID uint
 		}).
 		Limit(maxResults)
 
+	if filter.Since != 0 {
+		cte = buildWindowedRecordCTE(vehicleRoundRecordTable, vehicleRecordTable, columnVehicleID, vehicleID, filter.Since, columnTime, columnScore, columnKills, columnDeaths, columnRoadKills)
+	}
+
+	cte = addCountryFilter(cte, sqlutil.Qualify(playerTable, columnCountry), filter)
+
 	count, err := r.getEntryCount(ctx, cte)
 	if err != nil {
 		return nil, 0, err
@@ -359,6 +405,12 @@ func (r *Repository) FindTopPlayersByWeapon(ctx context.Context, weaponID uint8,
 		}).
 		Limit(maxResults)
 
+	if filter.Since != 0 {
+		cte = buildWindowedRecordCTE(weaponRoundRecordTable, weaponRecordTable, columnWeaponID, weaponID, filter.Since, columnTime, columnScore, columnKills, columnDeaths, columnShotsFired, columnShotsHit, columnTimesDeployed)
+	}
+
+	cte = addCountryFilter(cte, sqlutil.Qualify(playerTable, columnCountry), filter)
+
 	count, err := r.getEntryCount(ctx, cte)
 	if err != nil {
 		return nil, 0, err
@@ -414,9 +466,217 @@ func (r *Repository) FindTopPlayersByWeapon(ctx context.Context, weaponID uint8,
 	return entries, count, nil
 }
 
+func (r *Repository) FindTopPlayersByArmy(ctx context.Context, armyID uint8, by leaderboard.ArmyRankBy, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.ArmyRecord], int, error) {
+	const cteName = "l"
+
+	var rankColumn string
+	switch by {
+	case leaderboard.ArmyRankByScore:
+		rankColumn = sqlutil.Qualify(armyRecordTable, columnScore)
+	case leaderboard.ArmyRankByWins:
+		rankColumn = sqlutil.Qualify(armyRecordTable, columnWins)
+	default:
+		return nil, 0, fmt.Errorf("unknown army rank by: %d", by)
+	}
+
+	cte := sq.
+		Select(
+			// Using QualifyAlias here since some column names are not unique, e.g. "score"
+			sqlutil.QualifyAlias(armyRecordTable, columnPlayerID),
+			sqlutil.QualifyAlias(playerTable, columnName),
+			sqlutil.QualifyAlias(playerTable, columnJoined),
+			sqlutil.QualifyAlias(playerTable, columnCountry),
+			sqlutil.QualifyAlias(playerTable, columnTime),
+			sqlutil.QualifyAlias(playerTable, columnRankID),
+			sqlutil.QualifyAlias(playerTable, columnScore),
+			sqlutil.QualifyAlias(playerTable, columnCommandScore),
+			sqlutil.QualifyAlias(playerTable, columnCombatScore),
+			sqlutil.QualifyAlias(playerTable, columnTeamScore),
+			sqlutil.QualifyAlias(playerTable, columnKills),
+			sqlutil.QualifyAlias(playerTable, columnCommandTime),
+			sqlutil.QualifyAlias(armyRecordTable, columnArmyID),
+			sqlutil.QualifyAlias(armyRecordTable, columnTime),
+			sqlutil.QualifyAlias(armyRecordTable, columnWins),
+			sqlutil.QualifyAlias(armyRecordTable, columnLosses),
+			sqlutil.QualifyAlias(armyRecordTable, columnScore),
+			buildPositionColumnExpr(rankColumn, sqlutil.Qualify(playerTable, columnName)),
+		).
+		From(armyRecordTable).
+		InnerJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			playerTable,
+			sqlutil.Qualify(armyRecordTable, columnPlayerID),
+			sqlutil.Qualify(playerTable, columnID),
+		)).
+		Where(sq.And{
+			sq.Eq{sqlutil.Qualify(armyRecordTable, columnArmyID): armyID},
+			sq.Gt{rankColumn: 0},
+		}).
+		Limit(maxResults)
+
+	cte = addCountryFilter(cte, sqlutil.Qualify(playerTable, columnCountry), filter)
+
+	count, err := r.getEntryCount(ctx, cte)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := sq.
+		Select("*").
+		FromSelect(cte, cteName)
+
+	query = addFilter(query, sqlutil.Predicate(armyRecordTable, columnPlayerID), filter)
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]leaderboard.Entry[leaderboard.ArmyRecord], 0, max(filter.Last-filter.First, 1))
+	for rows.Next() {
+		var entry leaderboard.Entry[leaderboard.ArmyRecord]
+		if err = rows.Scan(
+			&entry.Data.Player.ID,
+			&entry.Data.Player.Name,
+			&entry.Data.Player.Joined,
+			&entry.Data.Player.Country,
+			&entry.Data.Player.Time,
+			&entry.Data.Player.Rank.ID,
+			&entry.Data.Player.Score,
+			&entry.Data.Player.CommandScore,
+			&entry.Data.Player.CombatScore,
+			&entry.Data.Player.TeamScore,
+			&entry.Data.Player.Kills,
+			&entry.Data.Player.CommandTime,
+			&entry.Data.Army.ID,
+			&entry.Data.Time,
+			&entry.Data.Wins,
+			&entry.Data.Losses,
+			&entry.Data.Score,
+			&entry.Position,
+		); err != nil {
+			return nil, 0, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, count, nil
+}
+
+func (r *Repository) FindTopPlayersByField(ctx context.Context, fieldID uint16, by leaderboard.FieldRankBy, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.FieldRecord], int, error) {
+	const cteName = "l"
+
+	var rankColumn string
+	switch by {
+	case leaderboard.FieldRankByWins:
+		rankColumn = sqlutil.Qualify(fieldRecordTable, columnWins)
+	case leaderboard.FieldRankByTime:
+		rankColumn = sqlutil.Qualify(fieldRecordTable, columnTime)
+	default:
+		return nil, 0, fmt.Errorf("unknown field rank by: %d", by)
+	}
+
+	cte := sq.
+		Select(
+			// Using QualifyAlias here since some column names are not unique, e.g. "time"
+			sqlutil.QualifyAlias(fieldRecordTable, columnPlayerID),
+			sqlutil.QualifyAlias(playerTable, columnName),
+			sqlutil.QualifyAlias(playerTable, columnJoined),
+			sqlutil.QualifyAlias(playerTable, columnCountry),
+			sqlutil.QualifyAlias(playerTable, columnTime),
+			sqlutil.QualifyAlias(playerTable, columnRankID),
+			sqlutil.QualifyAlias(playerTable, columnScore),
+			sqlutil.QualifyAlias(playerTable, columnCommandScore),
+			sqlutil.QualifyAlias(playerTable, columnCombatScore),
+			sqlutil.QualifyAlias(playerTable, columnTeamScore),
+			sqlutil.QualifyAlias(playerTable, columnKills),
+			sqlutil.QualifyAlias(playerTable, columnCommandTime),
+			sqlutil.QualifyAlias(fieldRecordTable, columnFieldID),
+			sqlutil.QualifyAlias(fieldRecordTable, columnTime),
+			sqlutil.QualifyAlias(fieldRecordTable, columnWins),
+			sqlutil.QualifyAlias(fieldRecordTable, columnLosses),
+			buildPositionColumnExpr(rankColumn, sqlutil.Qualify(playerTable, columnName)),
+		).
+		From(fieldRecordTable).
+		InnerJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			playerTable,
+			sqlutil.Qualify(fieldRecordTable, columnPlayerID),
+			sqlutil.Qualify(playerTable, columnID),
+		)).
+		Where(sq.And{
+			sq.Eq{sqlutil.Qualify(fieldRecordTable, columnFieldID): fieldID},
+			sq.Gt{rankColumn: 0},
+		}).
+		Limit(maxResults)
+
+	cte = addCountryFilter(cte, sqlutil.Qualify(playerTable, columnCountry), filter)
+
+	count, err := r.getEntryCount(ctx, cte)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := sq.
+		Select("*").
+		FromSelect(cte, cteName)
+
+	query = addFilter(query, sqlutil.Predicate(fieldRecordTable, columnPlayerID), filter)
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]leaderboard.Entry[leaderboard.FieldRecord], 0, max(filter.Last-filter.First, 1))
+	for rows.Next() {
+		var entry leaderboard.Entry[leaderboard.FieldRecord]
+		if err = rows.Scan(
+			&entry.Data.Player.ID,
+			&entry.Data.Player.Name,
+			&entry.Data.Player.Joined,
+			&entry.Data.Player.Country,
+			&entry.Data.Player.Time,
+			&entry.Data.Player.Rank.ID,
+			&entry.Data.Player.Score,
+			&entry.Data.Player.CommandScore,
+			&entry.Data.Player.CombatScore,
+			&entry.Data.Player.TeamScore,
+			&entry.Data.Player.Kills,
+			&entry.Data.Player.CommandTime,
+			&entry.Data.Field.ID,
+			&entry.Data.Time,
+			&entry.Data.Wins,
+			&entry.Data.Losses,
+			&entry.Position,
+		); err != nil {
+			return nil, 0, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, count, nil
+}
+
 func (r *Repository) FindRisingStars(ctx context.Context, filter leaderboard.Filter) ([]leaderboard.Entry[leaderboard.RisingStar], int, error) {
 	const cteName = "l"
 
+	// Stored positions are global, so they need to be re-computed within the country when filtering by country
+	positionColumn := sqlutil.Qualify(risingStarTable, columnPosition)
+	if filter.Country != "" {
+		positionColumn = fmt.Sprintf("ROW_NUMBER() OVER (ORDER BY %s ASC) AS %s", positionColumn, columnPosition)
+	}
+
 	cte := sq.
 		Select(
 			sqlutil.Qualify(risingStarTable, columnPlayerID),
@@ -432,7 +692,7 @@ func (r *Repository) FindRisingStars(ctx context.Context, filter leaderboard.Fil
 			sqlutil.Qualify(playerTable, columnKills),
 			sqlutil.Qualify(playerTable, columnCommandTime),
 			sqlutil.Qualify(risingStarTable, columnWeeklyScore),
-			sqlutil.Qualify(risingStarTable, columnPosition),
+			positionColumn,
 		).
 		From(risingStarTable).
 		InnerJoin(fmt.Sprintf(
@@ -443,6 +703,8 @@ func (r *Repository) FindRisingStars(ctx context.Context, filter leaderboard.Fil
 		)).
 		Limit(maxResults)
 
+	cte = addCountryFilter(cte, sqlutil.Qualify(playerTable, columnCountry), filter)
+
 	count, err := r.getEntryCount(ctx, cte)
 	if err != nil {
 		return nil, 0, err
@@ -492,8 +754,24 @@ func (r *Repository) FindRisingStars(ctx context.Context, filter leaderboard.Fil
 }
 
 func (r *Repository) GetRisingStarUpdateTimestamp(ctx context.Context) (uint32, error) {
+	// Prefer the recorded time of the last rebuild
 	query := sq.
-		// The table is ALTER-ed when re-building the rising star leaderboard, causing create_time to change
+		Select(fmt.Sprintf("MAX(%s)", columnTimestamp)).
+		From(risingStarUpdateTable)
+
+	var timestamp sql.NullInt64
+	err := query.RunWith(r.runner).QueryRowContext(ctx).Scan(&timestamp)
+	// Rebuilds are not recorded in databases which have not been migrated (yet)
+	if err != nil && !sqlutil.IsMissingTable(err) {
+		return 0, err
+	}
+	if timestamp.Valid {
+		return uint32(timestamp.Int64), nil
+	}
+
+	// Fall back to the table's create_time for rising stars rebuilt externally, since the table is ALTER-ed when doing
+	// so, causing create_time to change
+	query = sq.
 		Select("UNIX_TIMESTAMP(CREATE_TIME)").
 		From("INFORMATION_SCHEMA.TABLES").
 		Where(sq.And{
@@ -503,12 +781,18 @@ func (r *Repository) GetRisingStarUpdateTimestamp(ctx context.Context) (uint32,
 			},
 		})
 
-	var timestamp uint32
-	if err := query.RunWith(r.runner).QueryRowContext(ctx).Scan(&timestamp); err != nil {
+	var created uint32
+	if err = query.RunWith(r.runner).QueryRowContext(ctx).Scan(&created); err != nil {
 		return 0, err
 	}
 
-	return timestamp, nil
+	return created, nil
+}
+
+// GetUpdateTimestamp Leaderboards are computed on demand, so they are always up-to-date
+func (r *Repository) GetUpdateTimestamp(_ context.Context, _ leaderboard.Filter) (uint32, error) {
+	// Will overflow on 7 February 2106 at 06:28:15 UTC
+	return uint32(time.Now().UTC().Unix()), nil
 }
 
 func (r *Repository) getEntryCount(ctx context.Context, cte sq.SelectBuilder) (int, error) {
@@ -526,6 +810,92 @@ func (r *Repository) getEntryCount(ctx context.Context, cte sq.SelectBuilder) (i
 	return count, nil
 }
 
+type RisingStarRepository struct {
+	db *sql.DB
+}
+
+func NewRisingStarRepository(db *sql.DB) *RisingStarRepository {
+	return &RisingStarRepository{
+		db: db,
+	}
+}
+
+func (r *RisingStarRepository) Rebuild(ctx context.Context, since uint32, timestamp uint32) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback is a noop if the transaction has already been committed
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// Not using TRUNCATE here, since it would implicitly commit the transaction
+	if _, err = sq.Delete(risingStarTable).RunWith(tx).ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to delete rising stars: %w", err)
+	}
+
+	scoreSum := fmt.Sprintf("SUM(%s)", sqlutil.Qualify(playerRoundTable, columnScore))
+	gains := sq.
+		Select(
+			fmt.Sprintf("ROW_NUMBER() OVER (ORDER BY %s DESC, %s ASC)", scoreSum, sqlutil.Qualify(playerTable, columnName)),
+			sqlutil.Qualify(playerRoundTable, columnPlayerID),
+			fmt.Sprintf("%s * %d", scoreSum, weeklyScoreScale),
+		).
+		From(playerRoundTable).
+		InnerJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			roundTable,
+			sqlutil.Qualify(playerRoundTable, columnRoundID),
+			sqlutil.Qualify(roundTable, columnID),
+		)).
+		InnerJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			playerTable,
+			sqlutil.Qualify(playerRoundTable, columnPlayerID),
+			sqlutil.Qualify(playerTable, columnID),
+		)).
+		Where(sq.GtOrEq{sqlutil.Qualify(roundTable, columnEnd): since}).
+		GroupBy(
+			sqlutil.Qualify(playerRoundTable, columnPlayerID),
+			sqlutil.Qualify(playerTable, columnName),
+		).
+		Having(fmt.Sprintf("%s > 0", scoreSum)).
+		OrderBy(fmt.Sprintf("%s DESC", scoreSum)).
+		Limit(maxResults)
+
+	insert := sq.
+		Insert(risingStarTable).
+		Columns(
+			columnPosition,
+			columnPlayerID,
+			columnWeeklyScore,
+		).
+		Select(gains)
+
+	if _, err = insert.RunWith(tx).ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to insert rising stars: %w", err)
+	}
+
+	update := sq.
+		Insert(risingStarUpdateTable).
+		Columns(columnTimestamp).
+		Values(timestamp)
+
+	// Rising stars are still usable without recording the rebuild (the timestamp falls back to the table's create_time),
+	// so don't fail the rebuild over databases which have not been migrated (yet)
+	if _, err = update.RunWith(tx).ExecContext(ctx); err != nil {
+		if !sqlutil.IsMissingTable(err) {
+			return fmt.Errorf("failed to record rising star update: %w", err)
+		}
+		log.Ctx(ctx).Warn().
+			Err(err).
+			Msg("Failed to record rising star update, table is missing")
+	}
+
+	return tx.Commit()
+}
+
 func addFilter(query sq.SelectBuilder, column string, filter leaderboard.Filter) sq.SelectBuilder {
 	if filter.PID != nil {
 		return query.Where(sq.Eq{column: *filter.PID})
@@ -536,6 +906,110 @@ func addFilter(query sq.SelectBuilder, column string, filter leaderboard.Filter)
 		Limit(uint64(filter.Last - filter.First))
 }
 
+// buildWindowedScoreCTE Builds a CTE equivalent to the lifetime score leaderboard CTE, but summing up per-round
+// records of rounds ended since the given timestamp
+func buildWindowedScoreCTE(scoreColumn string, since uint32) sq.SelectBuilder {
+	sum := func(column string) string {
+		return fmt.Sprintf("SUM(%s)", sqlutil.Qualify(playerRoundTable, column))
+	}
+
+	return sq.
+		Select(
+			sqlutil.Qualify(playerTable, columnID),
+			sqlutil.Qualify(playerTable, columnName),
+			sqlutil.Qualify(playerTable, columnJoined),
+			sqlutil.Qualify(playerTable, columnCountry),
+			fmt.Sprintf("%s AS %s", sum(columnTime), columnTime),
+			sqlutil.Qualify(playerTable, columnRankID),
+			fmt.Sprintf("%s AS %s", sum(columnScore), columnScore),
+			fmt.Sprintf("%s AS %s", sum(columnCommandScore), columnCommandScore),
+			fmt.Sprintf("%s AS %s", sum(columnCombatScore), columnCombatScore),
+			fmt.Sprintf("%s AS %s", sum(columnTeamScore), columnTeamScore),
+			fmt.Sprintf("%s AS %s", sum(columnKills), columnKills),
+			fmt.Sprintf("%s AS %s", sum(columnCommandTime), columnCommandTime),
+			buildPositionColumnExpr(sum(scoreColumn), sqlutil.Qualify(playerTable, columnName)),
+		).
+		From(playerRoundTable).
+		InnerJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			roundTable,
+			sqlutil.Qualify(playerRoundTable, columnRoundID),
+			sqlutil.Qualify(roundTable, columnID),
+		)).
+		InnerJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			playerTable,
+			sqlutil.Qualify(playerRoundTable, columnPlayerID),
+			sqlutil.Qualify(playerTable, columnID),
+		)).
+		Where(sq.GtOrEq{sqlutil.Qualify(roundTable, columnEnd): since}).
+		GroupBy(sqlutil.Qualify(playerTable, columnID)).
+		Having(fmt.Sprintf("%s > 0", sum(scoreColumn))).
+		Limit(maxResults)
+}
+
+// buildWindowedRecordCTE Builds a CTE equivalent to the lifetime kit/vehicle/weapon leaderboard CTEs, but summing up
+// per-round records of rounds ended since the given timestamp. Columns are aliased like their lifetime counterparts
+// and players' stubs still contain lifetime values.
+func buildWindowedRecordCTE(table, lifetimeTable, idColumn string, id uint8, since uint32, sumColumns ...string) sq.SelectBuilder {
+	columns := []string{
+		fmt.Sprintf("%s AS %s", sqlutil.Qualify(playerTable, columnID), sqlutil.Predicate(lifetimeTable, columnPlayerID)),
+		sqlutil.QualifyAlias(playerTable, columnName),
+		sqlutil.QualifyAlias(playerTable, columnJoined),
+		sqlutil.QualifyAlias(playerTable, columnCountry),
+		sqlutil.QualifyAlias(playerTable, columnTime),
+		sqlutil.QualifyAlias(playerTable, columnRankID),
+		sqlutil.QualifyAlias(playerTable, columnScore),
+		sqlutil.QualifyAlias(playerTable, columnCommandScore),
+		sqlutil.QualifyAlias(playerTable, columnCombatScore),
+		sqlutil.QualifyAlias(playerTable, columnTeamScore),
+		sqlutil.QualifyAlias(playerTable, columnKills),
+		sqlutil.QualifyAlias(playerTable, columnCommandTime),
+		fmt.Sprintf("%s AS %s", sqlutil.Qualify(table, idColumn), sqlutil.Predicate(lifetimeTable, idColumn)),
+	}
+	for _, column := range sumColumns {
+		columns = append(columns, fmt.Sprintf("SUM(%s) AS %s", sqlutil.Qualify(table, column), sqlutil.Predicate(lifetimeTable, column)))
+	}
+
+	kills := fmt.Sprintf("SUM(%s)", sqlutil.Qualify(table, columnKills))
+	columns = append(columns, buildPositionColumnExpr(kills, sqlutil.Qualify(playerTable, columnName)))
+
+	return sq.
+		Select(columns...).
+		From(table).
+		InnerJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			roundTable,
+			sqlutil.Qualify(table, columnRoundID),
+			sqlutil.Qualify(roundTable, columnID),
+		)).
+		InnerJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			playerTable,
+			sqlutil.Qualify(table, columnPlayerID),
+			sqlutil.Qualify(playerTable, columnID),
+		)).
+		Where(sq.And{
+			sq.Eq{sqlutil.Qualify(table, idColumn): id},
+			sq.GtOrEq{sqlutil.Qualify(roundTable, columnEnd): since},
+		}).
+		GroupBy(
+			sqlutil.Qualify(playerTable, columnID),
+			sqlutil.Qualify(table, idColumn),
+		).
+		Having(fmt.Sprintf("%s > 0", kills)).
+		Limit(maxResults)
+}
+
+// addCountryFilter Country codes are compared using the column's collation, which is case-insensitive by default
+func addCountryFilter(query sq.SelectBuilder, column string, filter leaderboard.Filter) sq.SelectBuilder {
+	if filter.Country == "" {
+		return query
+	}
+
+	return query.Where(sq.Eq{column: filter.Country})
+}
+
 func buildPositionColumnExpr(column, tiebreaker string) string {
 	return fmt.Sprintf("RANK() OVER (ORDER BY %s DESC, %s ASC) AS %s", column, tiebreaker, virtualColumnPosition)
 }
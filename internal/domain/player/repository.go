@@ -26,4 +26,6 @@ type Repository interface {
 	ResetRankChangeFlags(ctx context.Context, id uint32) error
 	FindByID(ctx context.Context, id uint32) (Player, error)
 	FindWithNameMatching(ctx context.Context, name string, condition MatchCondition, order SortOrder) ([]Player, error)
+	// Search Returns the requested page of players matching the options, along with the total number of matches
+	Search(ctx context.Context, opts SearchOptions) ([]Player, int, error)
 }
@@ -0,0 +1,65 @@
+package player
+
+import (
+	"strings"
+)
+
+type SearchSort int
+
+const (
+	SearchSortName SearchSort = iota
+	SearchSortScore
+	SearchSortRank
+	SearchSortLastOnline
+)
+
+// SearchOptions Options for searching players by name. Names are matched case-insensitively and without clan tags.
+type SearchOptions struct {
+	Name      string
+	Condition MatchCondition
+	// Fuzzy Match names within MaxDistance edits of the name instead of using the condition (closest matches first)
+	Fuzzy       bool
+	MaxDistance int
+	Sort        SearchSort
+	Order       SortOrder
+	Offset      uint32
+	Limit       uint32
+}
+
+// StripClanTag Returns the name without the clan tag, which is separated from the nick by a space (e.g. "=TAG= nick")
+func StripClanTag(name string) string {
+	if i := strings.LastIndexByte(name, ' '); i != -1 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// Distance Returns the Levenshtein distance between a and b (number of single rune insertions, deletions or
+// substitutions required to change a into b)
+func Distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	if len(s) < len(t) {
+		s, t = t, s
+	}
+
+	// Only the previous row of the matrix is required to compute the current one
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(t)]
+}
@@ -0,0 +1,52 @@
+package player
+
+import (
+	"testing"
+)
+
+func TestStripClanTag(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "no clan tag", in: "nick", want: "nick"},
+		{name: "clan tag", in: "=TAG= nick", want: "nick"},
+		{name: "clan tag with spaces", in: "[A B] nick", want: "nick"},
+		{name: "trailing space", in: "nick ", want: ""},
+		{name: "empty", in: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripClanTag(tt.in); got != tt.want {
+				t.Errorf("StripClanTag(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want int
+	}{
+		{name: "equal", a: "nick", b: "nick", want: 0},
+		{name: "both empty", a: "", b: "", want: 0},
+		{name: "one empty", a: "", b: "nick", want: 4},
+		{name: "insertion", a: "nick", b: "nicks", want: 1},
+		{name: "deletion", a: "nicks", b: "nick", want: 1},
+		{name: "substitution", a: "nick", b: "nack", want: 1},
+		{name: "classic", a: "kitten", b: "sitting", want: 3},
+		{name: "case sensitive", a: "Nick", b: "nick", want: 1},
+		{name: "multi-byte runes", a: "nïck", b: "nick", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Distance(tt.a, tt.b); got != tt.want {
+				t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
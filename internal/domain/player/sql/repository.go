@@ -1,10 +1,14 @@
 package sql
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
 
 	sq "github.com/Masterminds/squirrel"
 
@@ -63,9 +67,17 @@ const (
 	columnTimesKicked       = "kicked"
 	columnTimesBanned       = "banned"
 	columnPermanentlyBanned = "permban"
+	// columnNick Indexed, generated column holding the lower case name without the clan tag
+	columnNick = "nick"
 
 	maxResults = 20
 	wildcard   = "%"
+
+	// maxSearchMatches Searches stop counting (and paginating) matches beyond this, since contains and ends-with searches
+	// cannot use the nick index to find matches and would otherwise need to scan every player
+	maxSearchMatches = 1000
+	// maxFuzzyCandidates Upper bound for the number of names to compute the edit distance for per fuzzy search
+	maxFuzzyCandidates = 5000
 )
 
 type Repository struct {
@@ -94,7 +106,305 @@ func (r *Repository) ResetRankChangeFlags(ctx context.Context, id uint32) error
 }
 
 func (r *Repository) FindByID(ctx context.Context, playerID uint32) (player.Player, error) {
+	query := selectPlayers().
+		Where(sq.Eq{columnID: playerID})
+
+	var p player.Player
+	if err := scanPlayer(query.RunWith(r.runner).QueryRowContext(ctx), &p); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return player.Player{}, player.ErrPlayerNotFound
+		}
+		return player.Player{}, err
+	}
+
+	return p, nil
+}
+
+func (r *Repository) FindWithNameMatching(ctx context.Context, name string, condition player.MatchCondition, order player.SortOrder) ([]player.Player, error) {
+	query := selectPlayers().
+		Limit(maxResults)
+
+	// LIKE values are parameterized and bound later, so string concatenation is build the *value* not the query
+	// Thus the only thing we need to escape are placeholders (%, _) to avoid expensive pattern searches
+	escaped := sqlutil.EscapeWildcards(name)
+	switch condition {
+	case player.MatchConditionContains:
+		query = query.Where(sq.Like{columnName: wildcard + escaped + wildcard})
+	case player.MatchConditionBeginsWith:
+		query = query.Where(sq.Like{columnName: escaped + wildcard})
+	case player.MatchConditionEndsWith:
+		query = query.Where(sq.Like{columnName: wildcard + escaped})
+	case player.MatchConditionEquals:
+		query = query.Where(sq.Eq{columnName: escaped})
+	default:
+		return nil, fmt.Errorf("unknown match condition: %d", condition)
+	}
+
+	switch order {
+	case player.SortOrderASC:
+		query = query.OrderBy(fmt.Sprintf("%s ASC", columnName))
+	case player.SortOrderDESC:
+		query = query.OrderBy(fmt.Sprintf("%s DESC", columnName))
+	default:
+		return nil, fmt.Errorf("unkown sort order: %d", order)
+	}
+
+	return r.findPlayers(ctx, query, maxResults)
+}
+
+func (r *Repository) Search(ctx context.Context, opts player.SearchOptions) ([]player.Player, int, error) {
+	if opts.Fuzzy {
+		return r.searchFuzzy(ctx, opts)
+	}
+
+	// Match on lower case names to not depend on the column's collation
+	name := strings.ToLower(player.StripClanTag(opts.Name))
+	escaped := sqlutil.EscapeWildcards(name)
+	var where sq.Sqlizer
+	switch opts.Condition {
+	case player.MatchConditionContains:
+		where = sq.Like{columnNick: wildcard + escaped + wildcard}
+	case player.MatchConditionBeginsWith:
+		where = sq.Like{columnNick: escaped + wildcard}
+	case player.MatchConditionEndsWith:
+		where = sq.Like{columnNick: wildcard + escaped}
+	case player.MatchConditionEquals:
+		where = sq.Eq{columnNick: name}
+	default:
+		return nil, 0, fmt.Errorf("unknown match condition: %d", opts.Condition)
+	}
+
+	orderBy, err := buildSearchOrderBy(opts.Sort, opts.Order)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matches := sq.
+		Select("1").
+		From(playerTable).
+		Where(where).
+		Limit(maxSearchMatches)
+	count := sq.
+		Select("COUNT(*)").
+		FromSelect(matches, "m")
+
+	var total int
+	if err = count.RunWith(r.runner).QueryRowContext(ctx).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	if opts.Offset >= maxSearchMatches {
+		return []player.Player{}, total, nil
+	}
+
+	query := selectPlayers().
+		Where(where).
+		OrderBy(orderBy...).
+		Offset(uint64(opts.Offset)).
+		Limit(uint64(min(opts.Limit, maxSearchMatches-opts.Offset)))
+
+	players, err := r.findPlayers(ctx, query, int(opts.Limit))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return players, total, nil
+}
+
+type candidate struct {
+	id         uint32
+	name       string
+	score      int64
+	rankID     uint8
+	lastOnline uint32
+	distance   int
+}
+
+// searchFuzzy Computes the edit distance for names which could possibly be within the max distance (based on the
+// length), so this is considerably more expensive than a regular search. Only names starting with the same character
+// are considered, which lets the query use the nick index (typos in the first character are comparatively rare).
+func (r *Repository) searchFuzzy(ctx context.Context, opts player.SearchOptions) ([]player.Player, int, error) {
+	// Order by is built purely to validate the options, sorting is done in memory
+	if _, err := buildSearchOrderBy(opts.Sort, opts.Order); err != nil {
+		return nil, 0, err
+	}
+
+	name := strings.ToLower(player.StripClanTag(opts.Name))
+	length := utf8.RuneCountInString(name)
+	where := sq.And{
+		sq.Expr(
+			"CHAR_LENGTH("+columnNick+") BETWEEN ? AND ?",
+			max(length-opts.MaxDistance, 0),
+			length+opts.MaxDistance,
+		),
+	}
+	if first, _ := utf8.DecodeRuneInString(name); first != utf8.RuneError {
+		where = append(where, sq.Like{columnNick: sqlutil.EscapeWildcards(string(first)) + wildcard})
+	}
+
 	query := sq.
+		Select(
+			columnID,
+			columnNick,
+			columnScore,
+			columnRankID,
+			columnLastOnline,
+		).
+		From(playerTable).
+		Where(where).
+		// Candidates are not ordered by anything meaningful, so this only guards against (very) common first characters
+		Limit(maxFuzzyCandidates)
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	candidates := make([]candidate, 0)
+	for rows.Next() {
+		var c candidate
+		if err = rows.Scan(
+			&c.id,
+			&c.name,
+			&c.score,
+			&c.rankID,
+			&c.lastOnline,
+		); err != nil {
+			return nil, 0, err
+		}
+
+		c.distance = player.Distance(name, c.name)
+		if c.distance <= opts.MaxDistance {
+			candidates = append(candidates, c)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Closest matches first, requested sort only breaks ties
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if a.distance != b.distance {
+			return cmp.Compare(a.distance, b.distance)
+		}
+		return cmp.Or(
+			compareCandidates(a, b, opts.Sort)*int(opts.Order),
+			cmp.Compare(a.id, b.id),
+		)
+	})
+
+	// Same as regular searches, only the first matches can be paginated through
+	candidates = candidates[:min(len(candidates), maxSearchMatches)]
+
+	total := len(candidates)
+	start := min(int(opts.Offset), total)
+	end := min(start+int(opts.Limit), total)
+	page := candidates[start:end]
+	if len(page) == 0 {
+		return []player.Player{}, total, nil
+	}
+
+	ids := make([]uint32, 0, len(page))
+	for _, c := range page {
+		ids = append(ids, c.id)
+	}
+
+	found, err := r.findPlayers(ctx, selectPlayers().Where(sq.Eq{columnID: ids}), len(ids))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Players are not returned in any particular order, so restore the order of the page
+	byID := make(map[uint32]player.Player, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
+	}
+	players := make([]player.Player, 0, len(page))
+	for _, c := range page {
+		// Player may have been deleted in the meantime
+		if p, ok := byID[c.id]; ok {
+			players = append(players, p)
+		}
+	}
+
+	return players, total, nil
+}
+
+func (r *Repository) findPlayers(ctx context.Context, query sq.SelectBuilder, capacity int) ([]player.Player, error) {
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]player.Player, 0, capacity)
+	for rows.Next() {
+		var p player.Player
+		if err = scanPlayer(rows, &p); err != nil {
+			return nil, err
+		}
+
+		players = append(players, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return players, nil
+}
+
+func buildSearchOrderBy(sort player.SearchSort, order player.SortOrder) ([]string, error) {
+	var direction string
+	switch order {
+	case player.SortOrderASC:
+		direction = "ASC"
+	case player.SortOrderDESC:
+		direction = "DESC"
+	default:
+		return nil, fmt.Errorf("unkown sort order: %d", order)
+	}
+
+	var columns []string
+	switch sort {
+	case player.SearchSortName:
+		columns = []string{columnName}
+	case player.SearchSortScore:
+		columns = []string{columnScore}
+	case player.SearchSortRank:
+		// Score decides between players of the same rank
+		columns = []string{columnRankID, columnScore}
+	case player.SearchSortLastOnline:
+		columns = []string{columnLastOnline}
+	default:
+		return nil, fmt.Errorf("unknown search sort: %d", sort)
+	}
+
+	orderBy := make([]string, 0, len(columns)+1)
+	for _, column := range columns {
+		orderBy = append(orderBy, fmt.Sprintf("%s %s", column, direction))
+	}
+	// Ensure stable pages
+	orderBy = append(orderBy, fmt.Sprintf("%s ASC", columnID))
+
+	return orderBy, nil
+}
+
+func compareCandidates(a, b candidate, sort player.SearchSort) int {
+	switch sort {
+	case player.SearchSortScore:
+		return cmp.Compare(a.score, b.score)
+	case player.SearchSortRank:
+		return cmp.Or(cmp.Compare(a.rankID, b.rankID), cmp.Compare(a.score, b.score))
+	case player.SearchSortLastOnline:
+		return cmp.Compare(a.lastOnline, b.lastOnline)
+	default:
+		return cmp.Compare(a.name, b.name)
+	}
+}
+
+func selectPlayers() sq.SelectBuilder {
+	return sq.
 		Select(
 			columnID,
 			columnName,
@@ -145,11 +455,11 @@ func (r *Repository) FindByID(ctx context.Context, playerID uint32) (player.Play
 			columnTimesBanned,
 			columnPermanentlyBanned,
 		).
-		From(playerTable).
-		Where(sq.Eq{columnID: playerID})
+		From(playerTable)
+}
 
-	var p player.Player
-	if err := query.RunWith(r.runner).QueryRowContext(ctx).Scan(
+func scanPlayer(scanner sq.RowScanner, p *player.Player) error {
+	return scanner.Scan(
 		&p.ID,
 		&p.Name,
 		&p.Joined,
@@ -198,163 +508,5 @@ func (r *Repository) FindByID(ctx context.Context, playerID uint32) (player.Play
 		&p.TimesKicked,
 		&p.TimesBanned,
 		&p.PermanentlyBanned,
-	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return player.Player{}, player.ErrPlayerNotFound
-		}
-		return player.Player{}, err
-	}
-
-	return p, nil
-}
-
-func (r *Repository) FindWithNameMatching(ctx context.Context, name string, condition player.MatchCondition, order player.SortOrder) ([]player.Player, error) {
-	query := sq.
-		Select(
-			columnID,
-			columnName,
-			columnJoined,
-			columnLastOnline,
-			columnTime,
-			columnRounds,
-			columnRankID,
-			columnScore,
-			columnCommandScore,
-			columnCombatScore,
-			columnTeamScore,
-			columnKills,
-			columnDeaths,
-			columnCaptures,
-			columnNeutralizes,
-			columnCaptureAssists,
-			columnNeutralizeAssists,
-			columnDefends,
-			columnHeals,
-			columnRevives,
-			columnResupplies,
-			columnRepairs,
-			columnDamageAssists,
-			columnTargetAssists,
-			columnDriverSpecials,
-			columnDriverAssists,
-			columnTeamKills,
-			columnTeamDamage,
-			columnTeamVehicleDamage,
-			columnSuicides,
-			columnKillStreak,
-			columnDeathStreak,
-			columnCommandTime,
-			columnSquadLeaderTime,
-			columnSquadMemberTime,
-			columnLoneWolfTime,
-			columnTimeParachute,
-			columnWins,
-			columnLosses,
-			columnBestScore,
-			columnRankChanged,
-			columnRankDecreased,
-			columnMode0,
-			columnMode1,
-			columnMode2,
-			columnTimesKicked,
-			columnTimesBanned,
-			columnPermanentlyBanned,
-		).
-		From(playerTable).
-		Limit(maxResults)
-
-	// LIKE values are parameterized and bound later, so string concatenation is build the *value* not the query
-	// Thus the only thing we need to escape are placeholders (%, _) to avoid expensive pattern searches
-	escaped := sqlutil.EscapeWildcards(name)
-	switch condition {
-	case player.MatchConditionContains:
-		query = query.Where(sq.Like{columnName: wildcard + escaped + wildcard})
-	case player.MatchConditionBeginsWith:
-		query = query.Where(sq.Like{columnName: escaped + wildcard})
-	case player.MatchConditionEndsWith:
-		query = query.Where(sq.Like{columnName: wildcard + escaped})
-	case player.MatchConditionEquals:
-		query = query.Where(sq.Eq{columnName: escaped})
-	default:
-		return nil, fmt.Errorf("unknown match condition: %d", condition)
-	}
-
-	switch order {
-	case player.SortOrderASC:
-		query = query.OrderBy(fmt.Sprintf("%s ASC", columnName))
-	case player.SortOrderDESC:
-		query = query.OrderBy(fmt.Sprintf("%s DESC", columnName))
-	default:
-		return nil, fmt.Errorf("unkown sort order: %d", order)
-	}
-
-	rows, err := query.RunWith(r.runner).QueryContext(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	players := make([]player.Player, 0, maxResults)
-	for rows.Next() {
-		var p player.Player
-		if err = rows.Scan(
-			&p.ID,
-			&p.Name,
-			&p.Joined,
-			&p.LastOnline,
-			&p.Time,
-			&p.Rounds,
-			&p.Rank.ID,
-			&p.Score,
-			&p.CommandScore,
-			&p.CombatScore,
-			&p.TeamScore,
-			&p.Kills,
-			&p.Deaths,
-			&p.Captures,
-			&p.Neutralizes,
-			&p.CaptureAssists,
-			&p.NeutralizeAssists,
-			&p.Defends,
-			&p.Heals,
-			&p.Revives,
-			&p.Resupplies,
-			&p.Repairs,
-			&p.DamageAssists,
-			&p.TargetAssists,
-			&p.DriverSpecials,
-			&p.DriverAssists,
-			&p.TeamKills,
-			&p.TeamDamage,
-			&p.TeamVehicleDamage,
-			&p.Suicides,
-			&p.KillStreak,
-			&p.DeathStreak,
-			&p.CommandTime,
-			&p.SquadLeaderTime,
-			&p.SquadMemberTime,
-			&p.LoneWolfTime,
-			&p.TimeParachute,
-			&p.Wins,
-			&p.Losses,
-			&p.BestScore,
-			&p.RankChanged,
-			&p.RankDecreased,
-			&p.Mode0,
-			&p.Mode1,
-			&p.Mode2,
-			&p.TimesKicked,
-			&p.TimesBanned,
-			&p.PermanentlyBanned,
-		); err != nil {
-			return nil, err
-		}
-
-		players = append(players, p)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return players, nil
+	)
 }
@@ -0,0 +1,72 @@
+package sql
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/cetteup/gasp/internal/domain/player"
+)
+
+func TestBuildSearchOrderBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		sort    player.SearchSort
+		order   player.SortOrder
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "name ascending",
+			sort:  player.SearchSortName,
+			order: player.SortOrderASC,
+			want:  []string{"name ASC", "id ASC"},
+		},
+		{
+			name:  "score descending",
+			sort:  player.SearchSortScore,
+			order: player.SortOrderDESC,
+			want:  []string{"score DESC", "id ASC"},
+		},
+		{
+			name:  "rank descending breaks ties by score",
+			sort:  player.SearchSortRank,
+			order: player.SortOrderDESC,
+			want:  []string{"rank_id DESC", "score DESC", "id ASC"},
+		},
+		{
+			name:  "last online ascending",
+			sort:  player.SearchSortLastOnline,
+			order: player.SortOrderASC,
+			want:  []string{"lastonline ASC", "id ASC"},
+		},
+		{
+			name:    "unknown sort",
+			sort:    player.SearchSort(-1),
+			order:   player.SortOrderASC,
+			wantErr: true,
+		},
+		{
+			name:    "unknown order",
+			sort:    player.SearchSortName,
+			order:   player.SortOrder(0),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildSearchOrderBy(tt.sort, tt.order)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package round
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrRoundNotFound = errors.New("round not found")
+)
+
+type HistoryRepository interface {
+	FindByID(ctx context.Context, id uint32) (History, error)
+	// FindByPlayerID Returns the player's last rounds, most recent first
+	FindByPlayerID(ctx context.Context, playerID uint32, limit uint32) ([]PlayerHistory, error)
+	// Insert Records a round, returning its id
+	Insert(ctx context.Context, history History) (uint32, error)
+}
@@ -5,3 +5,119 @@ type Round struct {
 	ID  uint32
 	End uint32
 }
+
+// Summary Round details without per-player records
+type Summary struct {
+	ID uint32
+	// Start Will overflow on 7 February 2106 at 06:28:15 UTC
+	Start uint32
+	// End Will overflow on 7 February 2106 at 06:28:15 UTC
+	End    uint32
+	Field  FieldRef
+	Server ServerRef
+	Teams  []Team
+}
+
+// Duration Returns the duration of the round in seconds
+func (s Summary) Duration() uint32 {
+	if s.End < s.Start {
+		return 0
+	}
+	return s.End - s.Start
+}
+
+// History Round details including every participating player's record
+type History struct {
+	Summary
+	Players []PlayerRecord
+}
+
+// PlayerHistory A round from a single player's point of view
+type PlayerHistory struct {
+	Round  Summary
+	Record PlayerRecord
+}
+
+type Team struct {
+	// ID Team number as used by the game (1 or 2)
+	ID      uint8
+	Army    ArmyRef
+	Tickets uint16
+	Winner  bool
+}
+
+type PlayerRecord struct {
+	Player       PlayerStub
+	Team         uint8
+	Score        int
+	CommandScore int
+	CombatScore  int
+	TeamScore    int
+	Kills        uint32
+	Deaths       uint32
+	Time         uint32
+	CommandTime  uint32
+	Kits         []KitRecord
+	// Vehicles Only recorded (for time-windowed leaderboards), not loaded with the rest of the record
+	Vehicles []VehicleRecord
+	// Weapons Only recorded (for time-windowed leaderboards), not loaded with the rest of the record
+	Weapons []WeaponRecord
+}
+
+type KitRecord struct {
+	Kit    KitRef
+	Time   uint32
+	Score  int
+	Kills  uint32
+	Deaths uint32
+}
+
+type VehicleRecord struct {
+	Vehicle   VehicleRef
+	Time      uint32
+	Score     int
+	Kills     uint32
+	Deaths    uint32
+	RoadKills uint32
+}
+
+type WeaponRecord struct {
+	Weapon        WeaponRef
+	Time          uint32
+	Score         int
+	Kills         uint32
+	Deaths        uint32
+	ShotsFired    uint32
+	ShotsHit      uint32
+	TimesDeployed uint32
+}
+
+type PlayerStub struct {
+	ID   uint32
+	Name string
+}
+
+type FieldRef struct {
+	ID uint16
+}
+
+type ServerRef struct {
+	ID   uint32
+	Name string
+}
+
+type ArmyRef struct {
+	ID uint8
+}
+
+type KitRef struct {
+	ID uint8
+}
+
+type VehicleRef struct {
+	ID uint8
+}
+
+type WeaponRef struct {
+	ID uint8
+}
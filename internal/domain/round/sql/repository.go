@@ -0,0 +1,538 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/cetteup/gasp/internal/domain/round"
+	"github.com/cetteup/gasp/internal/sqlutil"
+)
+
+const (
+	roundTable       = "round"
+	teamTable        = "round_team"
+	playerRoundTable = "player_round"
+	kitRoundTable    = "player_round_kit"
+	// Per-round vehicle and weapon records are only written here, they are read by the (time-windowed) leaderboards
+	vehicleRoundTable = "player_round_vehicle"
+	weaponRoundTable  = "player_round_weapon"
+	playerTable       = "player"
+	serverTable       = "server"
+
+	columnID       = "id"
+	columnName     = "name"
+	columnStart    = "time_start"
+	columnEnd      = "time_end"
+	columnFieldID  = "map_id"
+	columnServerID = "server_id"
+
+	columnRoundID = "round_id"
+	columnTeam    = "team"
+	columnArmyID  = "army_id"
+	columnTickets = "tickets"
+	columnWinner  = "winner"
+
+	columnPlayerID     = "player_id"
+	columnScore        = "score"
+	columnCommandScore = "cmdscore"
+	columnCombatScore  = "skillscore"
+	columnTeamScore    = "teamscore"
+	columnKills        = "kills"
+	columnDeaths       = "deaths"
+	columnTime         = "time"
+	columnCommandTime  = "cmdtime"
+
+	columnKitID = "kit_id"
+
+	columnVehicleID = "vehicle_id"
+	columnRoadKills = "roadkills"
+
+	columnWeaponID      = "weapon_id"
+	columnShotsFired    = "fired"
+	columnShotsHit      = "hits"
+	columnTimesDeployed = "deployed"
+)
+
+type HistoryRepository struct {
+	runner sqlutil.TxRunner
+}
+
+func NewHistoryRepository(runner sqlutil.TxRunner) *HistoryRepository {
+	return &HistoryRepository{
+		runner: runner,
+	}
+}
+
+func (r *HistoryRepository) FindByID(ctx context.Context, id uint32) (round.History, error) {
+	query := buildSummaryQuery().
+		Where(sq.Eq{sqlutil.Qualify(roundTable, columnID): id})
+
+	var h round.History
+	if err := query.RunWith(r.runner).QueryRowContext(ctx).Scan(
+		&h.ID,
+		&h.Start,
+		&h.End,
+		&h.Field.ID,
+		&h.Server.ID,
+		&h.Server.Name,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return round.History{}, round.ErrRoundNotFound
+		}
+		return round.History{}, err
+	}
+
+	teams, err := r.findTeams(ctx, []uint32{id})
+	if err != nil {
+		return round.History{}, fmt.Errorf("failed to find teams: %w", err)
+	}
+	h.Teams = teams[id]
+
+	records, err := r.findPlayerRecords(ctx, sq.Eq{sqlutil.Qualify(playerRoundTable, columnRoundID): id})
+	if err != nil {
+		return round.History{}, fmt.Errorf("failed to find player records: %w", err)
+	}
+	h.Players = records[id]
+
+	kits, err := r.findKitRecords(ctx, sq.Eq{columnRoundID: id})
+	if err != nil {
+		return round.History{}, fmt.Errorf("failed to find kit records: %w", err)
+	}
+	for i := range h.Players {
+		h.Players[i].Kits = kits[kitKey{roundID: id, playerID: h.Players[i].Player.ID}]
+	}
+
+	return h, nil
+}
+
+func (r *HistoryRepository) FindByPlayerID(ctx context.Context, playerID uint32, limit uint32) ([]round.PlayerHistory, error) {
+	// Find the player's rounds first, then load everything else for just those rounds
+	query := buildSummaryQuery().
+		InnerJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			playerRoundTable,
+			sqlutil.Qualify(roundTable, columnID),
+			sqlutil.Qualify(playerRoundTable, columnRoundID),
+		)).
+		Where(sq.Eq{sqlutil.Qualify(playerRoundTable, columnPlayerID): playerID}).
+		OrderBy(fmt.Sprintf("%s DESC", sqlutil.Qualify(roundTable, columnEnd))).
+		Limit(uint64(limit))
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make([]round.PlayerHistory, 0, limit)
+	ids := make([]uint32, 0, limit)
+	for rows.Next() {
+		var h round.PlayerHistory
+		if err = rows.Scan(
+			&h.Round.ID,
+			&h.Round.Start,
+			&h.Round.End,
+			&h.Round.Field.ID,
+			&h.Round.Server.ID,
+			&h.Round.Server.Name,
+		); err != nil {
+			return nil, err
+		}
+
+		histories = append(histories, h)
+		ids = append(ids, h.Round.ID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return histories, nil
+	}
+
+	teams, err := r.findTeams(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find teams: %w", err)
+	}
+
+	records, err := r.findPlayerRecords(ctx, sq.Eq{
+		sqlutil.Qualify(playerRoundTable, columnRoundID):  ids,
+		sqlutil.Qualify(playerRoundTable, columnPlayerID): playerID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find player records: %w", err)
+	}
+
+	kits, err := r.findKitRecords(ctx, sq.Eq{
+		columnRoundID:  ids,
+		columnPlayerID: playerID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find kit records: %w", err)
+	}
+
+	for i := range histories {
+		id := histories[i].Round.ID
+		histories[i].Round.Teams = teams[id]
+		// Filtering by player id leaves at most one record per round
+		if len(records[id]) > 0 {
+			histories[i].Record = records[id][0]
+		}
+		histories[i].Record.Kits = kits[kitKey{roundID: id, playerID: playerID}]
+	}
+
+	return histories, nil
+}
+
+func (r *HistoryRepository) Insert(ctx context.Context, history round.History) (uint32, error) {
+	tx, err := r.runner.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	// Rollback is a noop if the transaction has already been committed
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := sq.
+		Insert(roundTable).
+		Columns(
+			columnStart,
+			columnEnd,
+			columnFieldID,
+			columnServerID,
+		).
+		Values(
+			history.Start,
+			history.End,
+			history.Field.ID,
+			history.Server.ID,
+		).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert round: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(history.Teams) > 0 {
+		teams := sq.
+			Insert(teamTable).
+			Columns(
+				columnRoundID,
+				columnTeam,
+				columnArmyID,
+				columnTickets,
+				columnWinner,
+			)
+		for _, team := range history.Teams {
+			teams = teams.Values(id, team.ID, team.Army.ID, team.Tickets, team.Winner)
+		}
+
+		if _, err = teams.RunWith(tx).ExecContext(ctx); err != nil {
+			return 0, fmt.Errorf("failed to insert teams: %w", err)
+		}
+	}
+
+	if len(history.Players) > 0 {
+		players := sq.
+			Insert(playerRoundTable).
+			Columns(
+				columnRoundID,
+				columnPlayerID,
+				columnTeam,
+				columnScore,
+				columnCommandScore,
+				columnCombatScore,
+				columnTeamScore,
+				columnKills,
+				columnDeaths,
+				columnTime,
+				columnCommandTime,
+			)
+		kits := sq.
+			Insert(kitRoundTable).
+			Columns(
+				columnRoundID,
+				columnPlayerID,
+				columnKitID,
+				columnTime,
+				columnScore,
+				columnKills,
+				columnDeaths,
+			)
+		vehicles := sq.
+			Insert(vehicleRoundTable).
+			Columns(
+				columnRoundID,
+				columnPlayerID,
+				columnVehicleID,
+				columnTime,
+				columnScore,
+				columnKills,
+				columnDeaths,
+				columnRoadKills,
+			)
+		weapons := sq.
+			Insert(weaponRoundTable).
+			Columns(
+				columnRoundID,
+				columnPlayerID,
+				columnWeaponID,
+				columnTime,
+				columnScore,
+				columnKills,
+				columnDeaths,
+				columnShotsFired,
+				columnShotsHit,
+				columnTimesDeployed,
+			)
+		var hasKits, hasVehicles, hasWeapons bool
+		for _, p := range history.Players {
+			players = players.Values(
+				id,
+				p.Player.ID,
+				p.Team,
+				p.Score,
+				p.CommandScore,
+				p.CombatScore,
+				p.TeamScore,
+				p.Kills,
+				p.Deaths,
+				p.Time,
+				p.CommandTime,
+			)
+			for _, k := range p.Kits {
+				kits = kits.Values(id, p.Player.ID, k.Kit.ID, k.Time, k.Score, k.Kills, k.Deaths)
+				hasKits = true
+			}
+			for _, v := range p.Vehicles {
+				vehicles = vehicles.Values(id, p.Player.ID, v.Vehicle.ID, v.Time, v.Score, v.Kills, v.Deaths, v.RoadKills)
+				hasVehicles = true
+			}
+			for _, w := range p.Weapons {
+				weapons = weapons.Values(id, p.Player.ID, w.Weapon.ID, w.Time, w.Score, w.Kills, w.Deaths, w.ShotsFired, w.ShotsHit, w.TimesDeployed)
+				hasWeapons = true
+			}
+		}
+
+		if _, err = players.RunWith(tx).ExecContext(ctx); err != nil {
+			return 0, fmt.Errorf("failed to insert player records: %w", err)
+		}
+
+		if hasKits {
+			if _, err = kits.RunWith(tx).ExecContext(ctx); err != nil {
+				return 0, fmt.Errorf("failed to insert kit records: %w", err)
+			}
+		}
+
+		if hasVehicles {
+			if _, err = vehicles.RunWith(tx).ExecContext(ctx); err != nil {
+				return 0, fmt.Errorf("failed to insert vehicle records: %w", err)
+			}
+		}
+
+		if hasWeapons {
+			if _, err = weapons.RunWith(tx).ExecContext(ctx); err != nil {
+				return 0, fmt.Errorf("failed to insert weapon records: %w", err)
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return uint32(id), nil
+}
+
+func (r *HistoryRepository) findTeams(ctx context.Context, roundIDs []uint32) (map[uint32][]round.Team, error) {
+	query := sq.
+		Select(
+			columnRoundID,
+			columnTeam,
+			columnArmyID,
+			columnTickets,
+			columnWinner,
+		).
+		From(teamTable).
+		Where(sq.Eq{columnRoundID: roundIDs}).
+		OrderBy(
+			fmt.Sprintf("%s ASC", columnRoundID),
+			fmt.Sprintf("%s ASC", columnTeam),
+		)
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make(map[uint32][]round.Team, len(roundIDs))
+	for rows.Next() {
+		var roundID uint32
+		var team round.Team
+		if err = rows.Scan(
+			&roundID,
+			&team.ID,
+			&team.Army.ID,
+			&team.Tickets,
+			&team.Winner,
+		); err != nil {
+			return nil, err
+		}
+
+		teams[roundID] = append(teams[roundID], team)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+// findPlayerRecords Returns matching player records grouped by round id, each round's records being ordered by score
+// (descending)
+func (r *HistoryRepository) findPlayerRecords(ctx context.Context, pred sq.Sqlizer) (map[uint32][]round.PlayerRecord, error) {
+	query := sq.
+		Select(
+			sqlutil.Qualify(playerRoundTable, columnPlayerID),
+			sqlutil.Qualify(playerTable, columnName),
+			sqlutil.Qualify(playerRoundTable, columnTeam),
+			sqlutil.Qualify(playerRoundTable, columnScore),
+			sqlutil.Qualify(playerRoundTable, columnCommandScore),
+			sqlutil.Qualify(playerRoundTable, columnCombatScore),
+			sqlutil.Qualify(playerRoundTable, columnTeamScore),
+			sqlutil.Qualify(playerRoundTable, columnKills),
+			sqlutil.Qualify(playerRoundTable, columnDeaths),
+			sqlutil.Qualify(playerRoundTable, columnTime),
+			sqlutil.Qualify(playerRoundTable, columnCommandTime),
+			sqlutil.Qualify(playerRoundTable, columnRoundID),
+		).
+		From(playerRoundTable).
+		InnerJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			playerTable,
+			sqlutil.Qualify(playerRoundTable, columnPlayerID),
+			sqlutil.Qualify(playerTable, columnID),
+		)).
+		Where(pred).
+		OrderBy(
+			fmt.Sprintf("%s ASC", sqlutil.Qualify(playerRoundTable, columnRoundID)),
+			fmt.Sprintf("%s DESC", sqlutil.Qualify(playerRoundTable, columnScore)),
+		)
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[uint32][]round.PlayerRecord)
+	for rows.Next() {
+		var roundID uint32
+		var record round.PlayerRecord
+		if err = rows.Scan(
+			&record.Player.ID,
+			&record.Player.Name,
+			&record.Team,
+			&record.Score,
+			&record.CommandScore,
+			&record.CombatScore,
+			&record.TeamScore,
+			&record.Kills,
+			&record.Deaths,
+			&record.Time,
+			&record.CommandTime,
+			&roundID,
+		); err != nil {
+			return nil, err
+		}
+
+		records[roundID] = append(records[roundID], record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+type kitKey struct {
+	roundID  uint32
+	playerID uint32
+}
+
+func (r *HistoryRepository) findKitRecords(ctx context.Context, pred sq.Sqlizer) (map[kitKey][]round.KitRecord, error) {
+	query := sq.
+		Select(
+			columnRoundID,
+			columnPlayerID,
+			columnKitID,
+			columnTime,
+			columnScore,
+			columnKills,
+			columnDeaths,
+		).
+		From(kitRoundTable).
+		Where(pred).
+		OrderBy(fmt.Sprintf("%s ASC", columnKitID))
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kits := make(map[kitKey][]round.KitRecord)
+	for rows.Next() {
+		var key kitKey
+		var record round.KitRecord
+		if err = rows.Scan(
+			&key.roundID,
+			&key.playerID,
+			&record.Kit.ID,
+			&record.Time,
+			&record.Score,
+			&record.Kills,
+			&record.Deaths,
+		); err != nil {
+			return nil, err
+		}
+
+		kits[key] = append(kits[key], record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return kits, nil
+}
+
+func buildSummaryQuery() sq.SelectBuilder {
+	return sq.
+		Select(
+			sqlutil.Qualify(roundTable, columnID),
+			sqlutil.Qualify(roundTable, columnStart),
+			sqlutil.Qualify(roundTable, columnEnd),
+			sqlutil.Qualify(roundTable, columnFieldID),
+			sqlutil.Qualify(roundTable, columnServerID),
+			// Rounds may outlive the server they were played on
+			fmt.Sprintf("COALESCE(%s, '')", sqlutil.Qualify(serverTable, columnName)),
+		).
+		From(roundTable).
+		LeftJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			serverTable,
+			sqlutil.Qualify(roundTable, columnServerID),
+			sqlutil.Qualify(serverTable, columnID),
+		))
+}
@@ -0,0 +1,26 @@
+package season
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrSeasonNotFound = errors.New("season not found")
+)
+
+type Repository interface {
+	FindAll(ctx context.Context) ([]Season, error)
+	FindByID(ctx context.Context, id uint32) (Season, error)
+	// FindCurrent Returns the running season
+	FindCurrent(ctx context.Context) (Season, error)
+	// Start Ends the running season (if any) and starts a new one, returning the new season
+	Start(ctx context.Context, name string, timestamp uint32) (Season, error)
+}
+
+type Archiver interface {
+	// Archive Copies all stats into the season's archive, replacing any previously archived stats
+	Archive(ctx context.Context, id uint32) error
+	// Reset Deletes all stats (but not players themselves)
+	Reset(ctx context.Context) error
+}
@@ -0,0 +1,32 @@
+package season
+
+import (
+	"context"
+)
+
+type Season struct {
+	ID   uint32
+	Name string
+	// Start Will overflow on 7 February 2106 at 06:28:15 UTC
+	Start uint32
+	// End Zero while the season is running
+	End uint32
+}
+
+// IsArchived Only ended seasons have been archived
+func (s Season) IsArchived() bool {
+	return s.End != 0
+}
+
+type contextKey struct{}
+
+// NewContext Returns a copy of ctx scoped to the given (archived) season
+func NewContext(ctx context.Context, id uint32) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext Returns the id of the season ctx is scoped to, if any
+func FromContext(ctx context.Context) (uint32, bool) {
+	id, ok := ctx.Value(contextKey{}).(uint32)
+	return id, ok
+}
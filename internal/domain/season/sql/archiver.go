@@ -0,0 +1,213 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/cetteup/gasp/internal/sqlutil"
+)
+
+var (
+	// operationalTables Tables holding data about the running instance, rather than stats. Tables added to the schema
+	// need to be added to either this list or statsTables, since any table not listed here is archived.
+	operationalTables = []string{
+		"clan",
+		"clan_member",
+		"event_outbox",
+		"event_player_state",
+		"event_watermark",
+		"job",
+		"season",
+		"server",
+		"server_address",
+	}
+	// statsTables Tables to empty when resetting stats (the player table is reset separately)
+	statsTables = []string{
+		"player_army",
+		"player_award",
+		"player_kill_history",
+		"player_kit",
+		"player_map",
+		"player_respec",
+		"player_round",
+		"player_round_kit",
+		"player_round_vehicle",
+		"player_round_weapon",
+		"player_unlock",
+		"player_unlock_revoked",
+		"player_vehicle",
+		"player_weapon",
+		"risingstar",
+		"risingstar_update",
+		"round_team",
+	}
+	// derivedTables Operational tables holding state derived from stats, emptied along with the stats so the state is
+	// rebuilt from the reset stats (rather than compared against the previous season's)
+	derivedTables = []string{
+		"event_player_state",
+		"event_watermark",
+	}
+	// playerStatsColumns Columns of the player table to reset, excluding details like name, join date or bans
+	playerStatsColumns = []string{
+		"time", "rounds", "rank_id", "score", "cmdscore", "skillscore", "teamscore", "kills", "deaths", "captures",
+		"neutralizes", "captureassists", "neutralizeassists", "defends", "heals", "revives", "resupplies", "repairs",
+		"damageassists", "targetassists", "driverspecials", "driverassists", "teamkills", "teamdamage",
+		"teamvehicledamage", "suicides", "killstreak", "deathstreak", "cmdtime", "sqltime", "sqmtime", "lwtime",
+		"timepara", "wins", "losses", "bestscore", "chng", "decr", "mode0", "mode1", "mode2",
+	}
+)
+
+// Archiver Archives seasons into separate schemas (one per season) containing a copy of all stats tables
+type Archiver struct {
+	db     *sql.DB
+	prefix string
+}
+
+func NewArchiver(db *sql.DB, prefix string) *Archiver {
+	return &Archiver{
+		db:     db,
+		prefix: prefix,
+	}
+}
+
+func (a *Archiver) Archive(ctx context.Context, id uint32) error {
+	schema := SchemaName(a.prefix, id)
+
+	// DDL statements implicitly commit any transaction, so there is no point in using one here
+	if _, err := a.db.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", sqlutil.Quote(schema))); err != nil {
+		return fmt.Errorf("failed to create archive schema: %w", err)
+	}
+
+	tables, err := a.findTables(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find tables: %w", err)
+	}
+
+	for _, table := range tables {
+		columns, err2 := a.findColumns(ctx, table)
+		if err2 != nil {
+			return fmt.Errorf("failed to find columns of table %s: %w", table, err2)
+		}
+
+		archived := sqlutil.QuoteJoin(schema, table, ".")
+		statements := []string{
+			fmt.Sprintf("DROP TABLE IF EXISTS %s", archived),
+			fmt.Sprintf("CREATE TABLE %s LIKE %s", archived, sqlutil.Quote(table)),
+			fmt.Sprintf("INSERT INTO %[1]s (%[3]s) SELECT %[3]s FROM %[2]s", archived, sqlutil.Quote(table), columns),
+		}
+		for _, statement := range statements {
+			if _, err = a.db.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("failed to archive table %s: %w", table, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *Archiver) Reset(ctx context.Context) error {
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback is a noop if the transaction has already been committed
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// Not using TRUNCATE here, since it would implicitly commit the transaction
+	for _, table := range slices.Concat(statsTables, derivedTables) {
+		if _, err = sq.Delete(table).RunWith(tx).ExecContext(ctx); err != nil {
+			return fmt.Errorf("failed to reset table %s: %w", table, err)
+		}
+	}
+
+	update := sq.Update("player")
+	for _, column := range playerStatsColumns {
+		update = update.Set(column, 0)
+	}
+
+	if _, err = update.RunWith(tx).ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to reset players: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (a *Archiver) findTables(ctx context.Context) ([]string, error) {
+	query := sq.
+		Select("TABLE_NAME").
+		From("INFORMATION_SCHEMA.TABLES").
+		Where(sq.And{
+			sq.Expr("TABLE_SCHEMA = (SELECT DATABASE())"),
+			sq.Eq{"TABLE_TYPE": "BASE TABLE"},
+			sq.NotEq{"TABLE_NAME": operationalTables},
+		})
+
+	rows, err := query.RunWith(a.db).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]string, 0)
+	for rows.Next() {
+		var table string
+		if err = rows.Scan(&table); err != nil {
+			return nil, err
+		}
+
+		tables = append(tables, table)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// findColumns Returns the table's quoted and comma-separated columns, excluding generated columns (which cannot be
+// inserted into)
+func (a *Archiver) findColumns(ctx context.Context, table string) (string, error) {
+	query := sq.
+		Select("COLUMN_NAME").
+		From("INFORMATION_SCHEMA.COLUMNS").
+		Where(sq.And{
+			sq.Expr("TABLE_SCHEMA = (SELECT DATABASE())"),
+			sq.Eq{"TABLE_NAME": table},
+			// Expression is empty (MySQL) or NULL (MariaDB) for regular columns
+			sq.Expr("COALESCE(GENERATION_EXPRESSION, '') = ''"),
+		}).
+		OrderBy("ORDINAL_POSITION ASC")
+
+	rows, err := query.RunWith(a.db).QueryContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	columns := make([]string, 0)
+	for rows.Next() {
+		var column string
+		if err = rows.Scan(&column); err != nil {
+			return "", err
+		}
+
+		columns = append(columns, sqlutil.Quote(column))
+	}
+
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+
+	return strings.Join(columns, ", "), nil
+}
+
+// SchemaName Returns the name of the schema holding the given season's archive
+func SchemaName(prefix string, id uint32) string {
+	return fmt.Sprintf("%s%d", prefix, id)
+}
@@ -0,0 +1,157 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/cetteup/gasp/internal/domain/season"
+)
+
+const (
+	seasonTable = "season"
+
+	columnID    = "id"
+	columnName  = "name"
+	columnStart = "start"
+	columnEnd   = "end"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r *Repository) FindAll(ctx context.Context) ([]season.Season, error) {
+	query := sq.
+		Select(
+			columnID,
+			columnName,
+			columnStart,
+			columnEnd,
+		).
+		From(seasonTable).
+		OrderBy(fmt.Sprintf("%s ASC", columnID))
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seasons := make([]season.Season, 0)
+	for rows.Next() {
+		var s season.Season
+		if err = rows.Scan(
+			&s.ID,
+			&s.Name,
+			&s.Start,
+			&s.End,
+		); err != nil {
+			return nil, err
+		}
+
+		seasons = append(seasons, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return seasons, nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id uint32) (season.Season, error) {
+	return r.findOne(ctx, sq.Eq{columnID: id})
+}
+
+func (r *Repository) FindCurrent(ctx context.Context) (season.Season, error) {
+	return r.findOne(ctx, sq.Eq{columnEnd: 0})
+}
+
+func (r *Repository) Start(ctx context.Context, name string, timestamp uint32) (season.Season, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return season.Season{}, err
+	}
+	// Rollback is a noop if the transaction has already been committed
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	end := sq.
+		Update(seasonTable).
+		Set(columnEnd, timestamp).
+		Where(sq.Eq{columnEnd: 0})
+
+	if _, err = end.RunWith(tx).ExecContext(ctx); err != nil {
+		return season.Season{}, fmt.Errorf("failed to end current season: %w", err)
+	}
+
+	start := sq.
+		Insert(seasonTable).
+		Columns(
+			columnName,
+			columnStart,
+			columnEnd,
+		).
+		Values(
+			name,
+			timestamp,
+			0,
+		)
+
+	res, err := start.RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return season.Season{}, fmt.Errorf("failed to start season: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return season.Season{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return season.Season{}, err
+	}
+
+	return season.Season{
+		ID:    uint32(id),
+		Name:  name,
+		Start: timestamp,
+	}, nil
+}
+
+func (r *Repository) findOne(ctx context.Context, pred any) (season.Season, error) {
+	query := sq.
+		Select(
+			columnID,
+			columnName,
+			columnStart,
+			columnEnd,
+		).
+		From(seasonTable).
+		Where(pred)
+
+	var s season.Season
+	if err := query.RunWith(r.db).QueryRowContext(ctx).Scan(
+		&s.ID,
+		&s.Name,
+		&s.Start,
+		&s.End,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return season.Season{}, season.ErrSeasonNotFound
+		}
+		return season.Season{}, err
+	}
+
+	return s, nil
+}
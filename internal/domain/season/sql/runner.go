@@ -0,0 +1,75 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/cetteup/gasp/internal/domain/season"
+	"github.com/cetteup/gasp/internal/sqlutil"
+)
+
+// Runner Runs queries against the archive of the season a context is scoped to (see season.NewContext), or the live
+// database otherwise. Statements are always executed against the live database, since archives are read-only.
+// The live database may be a sqlutil.ReplicaSet, in which case live queries are run on a replica.
+type Runner struct {
+	sqlutil.DB
+	prefix  string
+	connect func(schema string) *sql.DB
+
+	mu       sync.Mutex
+	archives map[uint32]*sql.DB
+}
+
+func NewRunner(db sqlutil.DB, prefix string, connect func(schema string) *sql.DB) *Runner {
+	return &Runner{
+		DB:       db,
+		prefix:   prefix,
+		connect:  connect,
+		archives: make(map[uint32]*sql.DB),
+	}
+}
+
+func (r *Runner) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return r.choose(ctx).QueryContext(ctx, query, args...)
+}
+
+func (r *Runner) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return r.choose(ctx).QueryRowContext(ctx, query, args...)
+}
+
+// Close Closes the connections to any archive, but not to the live database
+func (r *Runner) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for id, db := range r.archives {
+		if err := db.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(r.archives, id)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *Runner) choose(ctx context.Context) sqlutil.DB {
+	id, ok := season.FromContext(ctx)
+	if !ok {
+		return r.DB
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	db, ok := r.archives[id]
+	if !ok {
+		// Connections are opened lazily, since most archives will rarely (if ever) be queried
+		db = r.connect(SchemaName(r.prefix, id))
+		r.archives[id] = db
+	}
+
+	return db
+}
@@ -0,0 +1,14 @@
+package server
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrServerNotFound = errors.New("server not found")
+)
+
+type Repository interface {
+	FindAll(ctx context.Context) ([]Server, error)
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/netip"
+	"strings"
+)
+
+type Server struct {
+	ID   uint32
+	Name string
+	// AuthorizedAddresses Addresses/networks the server is allowed to call protected endpoints from
+	AuthorizedAddresses []netip.Prefix
+	// Key Shared secret the server needs to present, optional if empty
+	Key    string
+	Ranked bool
+}
+
+func (s Server) IsAuthorizedAddress(addr netip.Addr) bool {
+	// Remote addresses may be IPv4 addresses mapped to IPv6, which would not be contained in any IPv4 prefix
+	addr = addr.Unmap()
+	for _, prefix := range s.AuthorizedAddresses {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAddress Parses either a single IP address or a network in CIDR notation into a prefix
+func ParseAddress(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
@@ -0,0 +1,95 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/cetteup/gasp/internal/domain/server"
+	"github.com/cetteup/gasp/internal/sqlutil"
+)
+
+const (
+	serverTable        = "server"
+	serverAddressTable = "server_address"
+
+	columnID     = "id"
+	columnName   = "name"
+	columnKey    = "auth_key"
+	columnRanked = "ranked"
+
+	columnServerID = "server_id"
+	columnAddress  = "address"
+)
+
+type Repository struct {
+	runner sq.BaseRunner
+}
+
+func NewRepository(runner sq.BaseRunner) *Repository {
+	return &Repository{
+		runner: runner,
+	}
+}
+
+func (r *Repository) FindAll(ctx context.Context) ([]server.Server, error) {
+	query := sq.
+		Select(
+			sqlutil.Qualify(serverTable, columnID),
+			sqlutil.Qualify(serverTable, columnName),
+			sqlutil.Qualify(serverTable, columnKey),
+			sqlutil.Qualify(serverTable, columnRanked),
+			sqlutil.Qualify(serverAddressTable, columnAddress),
+		).
+		From(serverTable).
+		// Servers without any authorized addresses are still returned, but will never be matched
+		LeftJoin(fmt.Sprintf(
+			"%s ON %s = %s",
+			serverAddressTable,
+			sqlutil.Qualify(serverTable, columnID),
+			sqlutil.Qualify(serverAddressTable, columnServerID),
+		)).
+		OrderBy(fmt.Sprintf("%s ASC", sqlutil.Qualify(serverTable, columnID)))
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows are ordered by server id, so any address row either belongs to the last seen server or starts a new one
+	servers := make([]server.Server, 0)
+	for rows.Next() {
+		var s server.Server
+		var address sql.NullString
+		if err = rows.Scan(
+			&s.ID,
+			&s.Name,
+			&s.Key,
+			&s.Ranked,
+			&address,
+		); err != nil {
+			return nil, err
+		}
+
+		if len(servers) == 0 || servers[len(servers)-1].ID != s.ID {
+			servers = append(servers, s)
+		}
+
+		if address.Valid {
+			prefix, err2 := server.ParseAddress(address.String)
+			if err2 != nil {
+				return nil, fmt.Errorf("invalid address for server %d: %w", s.ID, err2)
+			}
+			last := &servers[len(servers)-1]
+			last.AuthorizedAddresses = append(last.AuthorizedAddresses, prefix)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return servers, nil
+}
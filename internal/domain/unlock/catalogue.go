@@ -0,0 +1,92 @@
+package unlock
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Catalogue Set of unlocks known to the backend, as defined in the database (and thus possibly by a mod)
+type Catalogue struct {
+	unlocks map[uint16]Unlock
+}
+
+func NewCatalogue(unlocks []Unlock) *Catalogue {
+	c := &Catalogue{
+		unlocks: make(map[uint16]Unlock, len(unlocks)),
+	}
+	for _, u := range unlocks {
+		c.unlocks[u.ID] = u
+	}
+	return c
+}
+
+func (c *Catalogue) Get(id uint16) (Unlock, error) {
+	u, ok := c.unlocks[id]
+	if !ok {
+		return Unlock{}, ErrUnlockNotFound
+	}
+	return u, nil
+}
+
+// CheckRequirements Ensures every unlock along the unlock's chain of requirements has been unlocked
+func (c *Catalogue) CheckRequirements(id uint16, records []Record) error {
+	unlocked := make(map[uint16]bool, len(records))
+	for _, record := range records {
+		if record.Unlocked {
+			unlocked[record.Unlock.ID] = true
+		}
+	}
+
+	return c.checkRequirements(id, unlocked, make(map[uint16]bool))
+}
+
+// checkRequirements Walks the requirements depth-first, path containing the unlocks currently being checked
+func (c *Catalogue) checkRequirements(id uint16, unlocked map[uint16]bool, path map[uint16]bool) error {
+	if path[id] {
+		return fmt.Errorf("%w: %d", ErrRequirementCycle, id)
+	}
+	path[id] = true
+	defer delete(path, id)
+
+	u, err := c.Get(id)
+	if err != nil {
+		return fmt.Errorf("%w: %d", err, id)
+	}
+
+	for _, parent := range u.Requires {
+		if !unlocked[parent.ID] {
+			return fmt.Errorf("%w: %d requires %d", ErrRequirementNotMet, id, parent.ID)
+		}
+		if err = c.checkRequirements(parent.ID, unlocked, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindDependents Returns the unlocked unlocks which (directly or indirectly) require the unlock, children first
+func (c *Catalogue) FindDependents(id uint16, records []Record) []uint16 {
+	dependents := make([]uint16, 0)
+	seen := map[uint16]bool{id: true}
+	c.findDependents(id, records, seen, &dependents)
+	return dependents
+}
+
+func (c *Catalogue) findDependents(id uint16, records []Record, seen map[uint16]bool, dependents *[]uint16) {
+	for _, record := range records {
+		if !record.Unlocked || seen[record.Unlock.ID] {
+			continue
+		}
+
+		u, ok := c.unlocks[record.Unlock.ID]
+		if !ok || !slices.ContainsFunc(u.Requires, func(parent Ref) bool { return parent.ID == id }) {
+			continue
+		}
+
+		seen[u.ID] = true
+		// Add the dependent's own dependents first, so revoking in order never leaves a dependent without its parent
+		c.findDependents(u.ID, records, seen, dependents)
+		*dependents = append(*dependents, u.ID)
+	}
+}
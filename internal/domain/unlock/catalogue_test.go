@@ -0,0 +1,89 @@
+package unlock
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// newTestCatalogue Returns a catalogue of two chains (1 <- 2 <- 3 and 1 <- 4), an unlock without requirements (5), an
+// unlock requiring an unknown unlock (6) and a cycle (7 <- 8 <- 7)
+func newTestCatalogue() *Catalogue {
+	return NewCatalogue([]Unlock{
+		{ID: 1},
+		{ID: 2, Requires: []Ref{{ID: 1}}},
+		{ID: 3, Requires: []Ref{{ID: 2}}},
+		{ID: 4, Requires: []Ref{{ID: 1}}},
+		{ID: 5},
+		{ID: 6, Requires: []Ref{{ID: 99}}},
+		{ID: 7, Requires: []Ref{{ID: 8}}},
+		{ID: 8, Requires: []Ref{{ID: 7}}},
+	})
+}
+
+func TestCatalogue_CheckRequirements(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      uint16
+		records []Record
+		wantErr error
+	}{
+		{name: "no requirements", id: 5},
+		{name: "no requirements but unlocked", id: 1, records: unlocked(1)},
+		{name: "direct requirement met", id: 2, records: unlocked(1)},
+		{name: "direct requirement not met", id: 2, wantErr: ErrRequirementNotMet},
+		{
+			name:    "direct requirement not marked unlocked",
+			id:      2,
+			records: []Record{{Unlock: Unlock{ID: 1}}},
+			wantErr: ErrRequirementNotMet,
+		},
+		{name: "chain met", id: 3, records: unlocked(1, 2)},
+		{name: "indirect requirement not met", id: 3, records: unlocked(2), wantErr: ErrRequirementNotMet},
+		{name: "unknown unlock", id: 100, wantErr: ErrUnlockNotFound},
+		{name: "unknown requirement", id: 6, records: unlocked(99), wantErr: ErrUnlockNotFound},
+		{name: "cycle", id: 7, records: unlocked(7, 8), wantErr: ErrRequirementCycle},
+	}
+	c := newTestCatalogue()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.CheckRequirements(tt.id, tt.records)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCatalogue_FindDependents(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      uint16
+		records []Record
+		want    []uint16
+	}{
+		{name: "nothing unlocked", id: 1, want: []uint16{}},
+		{name: "no dependents", id: 5, records: unlocked(1, 2, 3, 4, 5), want: []uint16{}},
+		{name: "leaf", id: 3, records: unlocked(1, 2, 3), want: []uint16{}},
+		{name: "direct dependent", id: 2, records: unlocked(1, 2, 3), want: []uint16{3}},
+		{name: "chain children first", id: 1, records: unlocked(1, 2, 3, 4), want: []uint16{3, 2, 4}},
+		{name: "only unlocked dependents", id: 1, records: unlocked(1, 4), want: []uint16{4}},
+		{
+			name:    "dependents not marked unlocked",
+			id:      1,
+			records: append(unlocked(1, 4), Record{Unlock: Unlock{ID: 2}}),
+			want:    []uint16{4},
+		},
+		{name: "cycle", id: 7, records: unlocked(7, 8), want: []uint16{8}},
+	}
+	c := newTestCatalogue()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.FindDependents(tt.id, tt.records); !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package unlock
+
+import (
+	"slices"
+
+	"github.com/cetteup/gasp/internal/domain/award"
+	"github.com/cetteup/gasp/internal/domain/player"
+)
+
+type PoolKind int
+
+const (
+	PoolKindEnlisted PoolKind = iota
+	PoolKindOfficer
+)
+
+// Policy Determines how many unlock points players are granted and which unlocks the points can be spent on
+type Policy struct {
+	Enlisted Pool
+	Officer  Pool
+}
+
+// Pool Set of unlocks along with the grants of points which can be spent on them
+type Pool struct {
+	// Unlocks IDs of the unlocks the pool's points can be spent on
+	Unlocks []uint16
+	// RankGrants Points granted upon reaching a rank, keyed by rank id
+	RankGrants  map[uint8]int
+	BadgeGrants []BadgeGrant
+}
+
+// BadgeGrant Points granted for earning a badge at the given level
+type BadgeGrant struct {
+	AwardID uint32
+	Level   uint64
+	Points  int
+}
+
+// DefaultPolicy Returns the policy used by the original backend: one enlisted point per rank from private first class
+// through staff sergeant and one per veteran kit badge, to be spent on the 14 kit unlocks. No officer points are granted.
+func DefaultPolicy() Policy {
+	badgeGrants := make([]BadgeGrant, 0, len(award.KitBadgeIDs))
+	for _, id := range award.KitBadgeIDs {
+		badgeGrants = append(badgeGrants, BadgeGrant{
+			AwardID: id,
+			Level:   2,
+			Points:  1,
+		})
+	}
+
+	return Policy{
+		Enlisted: Pool{
+			Unlocks:     []uint16{11, 22, 33, 44, 55, 66, 77, 88, 99, 111, 222, 333, 444, 555},
+			RankGrants:  map[uint8]int{2: 1, 3: 1, 4: 1, 5: 1, 6: 1, 7: 1, 8: 1},
+			BadgeGrants: badgeGrants,
+		},
+	}
+}
+
+// Pool Returns the kind of pool the unlock belongs to, false if the unlock is not part of any pool
+func (p Policy) Pool(unlockID uint16) (PoolKind, bool) {
+	switch {
+	case slices.Contains(p.Enlisted.Unlocks, unlockID):
+		return PoolKindEnlisted, true
+	case slices.Contains(p.Officer.Unlocks, unlockID):
+		return PoolKindOfficer, true
+	default:
+		return 0, false
+	}
+}
+
+func (p Policy) DetermineAvailablePoints(pl player.Player, unlockRecords []Record, awardRecords []award.Record) Points {
+	return Points{
+		Enlisted: p.Enlisted.determineAvailablePoints(pl, unlockRecords, awardRecords),
+		Officer:  p.Officer.determineAvailablePoints(pl, unlockRecords, awardRecords),
+	}
+}
+
+func (p Pool) determineAvailablePoints(pl player.Player, unlockRecords []Record, awardRecords []award.Record) int {
+	usedPoints := 0
+	for _, record := range unlockRecords {
+		if record.Unlocked && slices.Contains(p.Unlocks, record.Unlock.ID) {
+			usedPoints++
+		}
+	}
+
+	// Player cannot have any unlock points available if they already unlocked everything
+	if usedPoints >= len(p.Unlocks) {
+		return 0
+	}
+
+	grantedPoints := 0
+	for rankID, points := range p.RankGrants {
+		if pl.Rank.ID >= rankID {
+			grantedPoints += points
+		}
+	}
+
+	for _, grant := range p.BadgeGrants {
+		// Only grant points once per badge, even if the data in the db is inconsistent
+		if slices.ContainsFunc(awardRecords, func(record award.Record) bool {
+			return record.Award.Type == award.TypeBadge && record.Award.ID == grant.AwardID && record.Level == grant.Level
+		}) {
+			grantedPoints += grant.Points
+		}
+	}
+
+	return max(grantedPoints-usedPoints, 0)
+}
@@ -0,0 +1,163 @@
+package unlock
+
+import (
+	"testing"
+
+	"github.com/cetteup/gasp/internal/domain/award"
+	"github.com/cetteup/gasp/internal/domain/player"
+)
+
+func newTestPolicy() Policy {
+	policy := DefaultPolicy()
+	policy.Officer = Pool{
+		Unlocks:    []uint16{1001, 1002, 1003},
+		RankGrants: map[uint8]int{12: 1, 13: 1},
+		BadgeGrants: []BadgeGrant{
+			{AwardID: 1031406, Level: 1, Points: 1},
+		},
+	}
+	return policy
+}
+
+func unlocked(ids ...uint16) []Record {
+	records := make([]Record, 0, len(ids))
+	for _, id := range ids {
+		records = append(records, Record{Unlock: Unlock{ID: id}, Unlocked: true})
+	}
+	return records
+}
+
+func badge(id uint32, level uint64) award.Record {
+	return award.Record{Award: award.Award{ID: id, Type: award.TypeBadge}, Level: level}
+}
+
+func TestPolicy_Pool(t *testing.T) {
+	tests := []struct {
+		name     string
+		unlockID uint16
+		want     PoolKind
+		wantOK   bool
+	}{
+		{name: "enlisted unlock", unlockID: 11, want: PoolKindEnlisted, wantOK: true},
+		{name: "enlisted special forces unlock", unlockID: 555, want: PoolKindEnlisted, wantOK: true},
+		{name: "officer unlock", unlockID: 1002, want: PoolKindOfficer, wantOK: true},
+		{name: "unknown unlock", unlockID: 9999, wantOK: false},
+	}
+	policy := newTestPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := policy.Pool(tt.unlockID)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Pool(%d) = (%d, %t), want (%d, %t)", tt.unlockID, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPolicy_DetermineAvailablePoints(t *testing.T) {
+	tests := []struct {
+		name          string
+		rankID        uint8
+		unlockRecords []Record
+		awardRecords  []award.Record
+		want          Points
+	}{
+		{
+			name:   "private",
+			rankID: 0,
+			want:   Points{},
+		},
+		{
+			name:   "private first class",
+			rankID: 2,
+			want:   Points{Enlisted: 1},
+		},
+		{
+			name:   "staff sergeant",
+			rankID: 8,
+			want:   Points{Enlisted: 7},
+		},
+		{
+			name:          "staff sergeant with spent points",
+			rankID:        8,
+			unlockRecords: unlocked(11, 22),
+			want:          Points{Enlisted: 5},
+		},
+		{
+			name:          "unlock records not marked unlocked are ignored",
+			rankID:        8,
+			unlockRecords: []Record{{Unlock: Unlock{ID: 11}}},
+			want:          Points{Enlisted: 7},
+		},
+		{
+			name:         "veteran kit badge",
+			rankID:       3,
+			awardRecords: []award.Record{badge(1031119, 2)},
+			want:         Points{Enlisted: 3},
+		},
+		{
+			name:         "basic kit badge",
+			rankID:       3,
+			awardRecords: []award.Record{badge(1031119, 1)},
+			want:         Points{Enlisted: 2},
+		},
+		{
+			name:         "duplicate badge records",
+			rankID:       3,
+			awardRecords: []award.Record{badge(1031119, 2), badge(1031119, 2)},
+			want:         Points{Enlisted: 3},
+		},
+		{
+			name:         "medal with badge id",
+			rankID:       3,
+			awardRecords: []award.Record{{Award: award.Award{ID: 1031119, Type: award.TypeMedal}, Level: 2}},
+			want:         Points{Enlisted: 2},
+		},
+		{
+			name:          "more points spent than granted",
+			rankID:        2,
+			unlockRecords: unlocked(11, 22, 33),
+			want:          Points{},
+		},
+		{
+			name:          "everything unlocked",
+			rankID:        13,
+			unlockRecords: unlocked(11, 22, 33, 44, 55, 66, 77, 88, 99, 111, 222, 333, 444, 555),
+			awardRecords:  []award.Record{badge(1031119, 2), badge(1031120, 2)},
+			want:          Points{Officer: 2},
+		},
+		{
+			name:   "officer",
+			rankID: 13,
+			want:   Points{Enlisted: 7, Officer: 2},
+		},
+		{
+			name:          "officer with spent points in both pools",
+			rankID:        13,
+			unlockRecords: unlocked(11, 1001),
+			awardRecords:  []award.Record{badge(1031406, 1)},
+			want:          Points{Enlisted: 6, Officer: 2},
+		},
+	}
+	policy := newTestPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := player.Player{Rank: player.RankRef{ID: tt.rankID}}
+			if got := policy.DetermineAvailablePoints(pl, tt.unlockRecords, tt.awardRecords); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDefaultPolicy_KitBadges(t *testing.T) {
+	policy := DefaultPolicy()
+	if len(policy.Enlisted.BadgeGrants) != len(award.KitBadgeIDs) {
+		t.Fatalf("expected %d badge grants, got %d", len(award.KitBadgeIDs), len(policy.Enlisted.BadgeGrants))
+	}
+	for _, grant := range policy.Enlisted.BadgeGrants {
+		if !award.IsKitBadge(grant.AwardID) {
+			t.Errorf("expected badge grant for kit badge, got award %d", grant.AwardID)
+		}
+	}
+}
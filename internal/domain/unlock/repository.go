@@ -7,6 +7,9 @@ import (
 
 var (
 	ErrRecordNotUnlocked = errors.New("record not unlocked")
+	ErrUnlockNotFound    = errors.New("unlock not found")
+	ErrRequirementNotMet = errors.New("unlock requirement not met")
+	ErrRequirementCycle  = errors.New("unlock requirements contain a cycle")
 )
 
 type Repository interface {
@@ -15,5 +18,15 @@ type Repository interface {
 
 type RecordRepository interface {
 	Insert(ctx context.Context, record Record) error
+	// FindByPlayerID Returns a record for every unlock the player has or can obtain
 	FindByPlayerID(ctx context.Context, playerID uint32) ([]Record, error)
+	// Revoke Revokes the player's unlocks (keeping them as history), optionally using up a free respec. Revoked unlocks
+	// can be selected again.
+	Revoke(ctx context.Context, playerID uint32, unlockIDs []uint16, timestamp uint32, useRespec bool) error
+}
+
+type RespecRepository interface {
+	// FindByPlayerID Returns the player's respecs, which are empty (rather than not found) for players without any
+	FindByPlayerID(ctx context.Context, playerID uint32) (Respecs, error)
+	SetAllowance(ctx context.Context, playerID uint32, allowance uint16) error
 }
@@ -2,6 +2,8 @@ package sql
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
@@ -12,9 +14,12 @@ import (
 )
 
 const (
-	unlockTable            = "unlock"
-	unlockRecordTable      = "player_unlock"
+	unlockTable       = "unlock"
+	unlockRecordTable = "player_unlock"
+	// revokedRecordTable Revoked unlock records are moved here, freeing up the unlock to be selected again
+	revokedRecordTable     = "player_unlock_revoked"
 	unlockRequirementTable = "unlock_requirement"
+	respecTable            = "player_respec"
 
 	columnID          = "id"
 	columnKitID       = "kit_id"
@@ -24,6 +29,10 @@ const (
 	columnPlayerID  = "player_id"
 	columnUnlockID  = "unlock_id"
 	columnTimestamp = "timestamp"
+	columnRevoked   = "revoked"
+
+	columnAllowance = "allowance"
+	columnUsed      = "used"
 
 	columnParentID = "parent_id"
 	columnChildID  = "child_id"
@@ -76,14 +85,62 @@ func (r *Repository) FindAll(ctx context.Context) ([]unlock.Unlock, error) {
 		return nil, err
 	}
 
+	requirements, err := r.findRequirements(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find requirements: %w", err)
+	}
+
+	for i := range unlocks {
+		unlocks[i].Requires = requirements[unlocks[i].ID]
+	}
+
 	return unlocks, nil
 }
 
+// findRequirements Returns the unlocks required by each unlock, keyed by the (child) unlock's id
+func (r *Repository) findRequirements(ctx context.Context) (map[uint16][]unlock.Ref, error) {
+	query := sq.
+		Select(
+			columnChildID,
+			columnParentID,
+		).
+		From(unlockRequirementTable).
+		OrderBy(
+			fmt.Sprintf("%s ASC", columnChildID),
+			fmt.Sprintf("%s ASC", columnParentID),
+		)
+
+	rows, err := query.RunWith(r.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requirements := make(map[uint16][]unlock.Ref)
+	for rows.Next() {
+		var childID uint16
+		var parent unlock.Ref
+		if err = rows.Scan(
+			&childID,
+			&parent.ID,
+		); err != nil {
+			return nil, err
+		}
+
+		requirements[childID] = append(requirements[childID], parent)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return requirements, nil
+}
+
 type RecordRepository struct {
-	runner sq.BaseRunner
+	runner sqlutil.TxRunner
 }
 
-func NewRecordRepository(runner sq.BaseRunner) *RecordRepository {
+func NewRecordRepository(runner sqlutil.TxRunner) *RecordRepository {
 	return &RecordRepository{
 		runner: runner,
 	}
@@ -247,3 +304,152 @@ func (r *RecordRepository) FindByPlayerID(ctx context.Context, playerID uint32)
 
 	return records, nil
 }
+
+func (r *RecordRepository) Revoke(ctx context.Context, playerID uint32, unlockIDs []uint16, timestamp uint32, useRespec bool) error {
+	tx, err := r.runner.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback is a noop if the transaction has already been committed
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	pred := sq.Eq{
+		columnPlayerID: playerID,
+		columnUnlockID: unlockIDs,
+	}
+
+	// Keep revoked records as history, including when they were unlocked in the first place
+	_, err = sq.
+		Insert(revokedRecordTable).
+		Columns(
+			columnPlayerID,
+			columnUnlockID,
+			columnTimestamp,
+			columnRevoked,
+		).
+		Select(sq.
+			Select(
+				columnPlayerID,
+				columnUnlockID,
+				columnTimestamp,
+				util.FormatUint(timestamp),
+			).
+			From(unlockRecordTable).
+			Where(pred),
+		).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to record revoked unlock records: %w", err)
+	}
+
+	res, err := sq.
+		Delete(unlockRecordTable).
+		Where(pred).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to revoke unlock records: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// Make sure we don't use up a respec without actually revoking anything
+	if affected == 0 {
+		return unlock.ErrRecordNotUnlocked
+	}
+
+	if useRespec {
+		_, err = sq.
+			Insert(respecTable).
+			Columns(
+				columnPlayerID,
+				columnUsed,
+			).
+			Values(
+				playerID,
+				1,
+			).
+			Suffix(fmt.Sprintf("ON DUPLICATE KEY UPDATE %[1]s = %[1]s + 1", columnUsed)).
+			RunWith(tx).
+			ExecContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to use respec: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+type RespecRepository struct {
+	runner sq.BaseRunner
+}
+
+func NewRespecRepository(runner sq.BaseRunner) *RespecRepository {
+	return &RespecRepository{
+		runner: runner,
+	}
+}
+
+func (r *RespecRepository) FindByPlayerID(ctx context.Context, playerID uint32) (unlock.Respecs, error) {
+	query := sq.
+		Select(
+			columnAllowance,
+			columnUsed,
+		).
+		From(respecTable).
+		Where(sq.Eq{columnPlayerID: playerID})
+
+	respecs := unlock.Respecs{
+		Player: unlock.PlayerRef{
+			ID: playerID,
+		},
+	}
+	var allowance sql.NullInt32
+	err := query.RunWith(r.runner).QueryRowContext(ctx).Scan(
+		&allowance,
+		&respecs.Used,
+	)
+	if err != nil {
+		// Players only get a row once they use a respec or are granted an allowance
+		if errors.Is(err, sql.ErrNoRows) {
+			return respecs, nil
+		}
+		return unlock.Respecs{}, err
+	}
+
+	if allowance.Valid {
+		a := uint16(allowance.Int32)
+		respecs.Allowance = &a
+	}
+
+	return respecs, nil
+}
+
+func (r *RespecRepository) SetAllowance(ctx context.Context, playerID uint32, allowance uint16) error {
+	query := sq.
+		Insert(respecTable).
+		Columns(
+			columnPlayerID,
+			columnAllowance,
+			columnUsed,
+		).
+		Values(
+			playerID,
+			allowance,
+			0,
+		).
+		Suffix(fmt.Sprintf("ON DUPLICATE KEY UPDATE %[1]s = VALUES(%[1]s)", columnAllowance))
+
+	_, err := query.RunWith(r.runner).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -1,19 +1,16 @@
 package unlock
 
-import (
-	"github.com/cetteup/gasp/internal/domain/award"
-	"github.com/cetteup/gasp/internal/domain/player"
-)
-
-const (
-	totalPossibleUnlocks = 7 * 2 // 7 classes, 2 unlocks each
-)
-
 type Unlock struct {
 	ID          uint16
 	Name        string
 	Description string
 	Kit         KitRef
+	// Requires Unlocks which need to be unlocked before this one can be (only populated by Repository.FindAll)
+	Requires []Ref
+}
+
+type Ref struct {
+	ID uint16
 }
 
 type KitRef struct {
@@ -27,35 +24,40 @@ type Record struct {
 	Timestamp uint32 // zero if unlocked is false
 }
 
-type PlayerRef struct {
-	ID uint32
+// Respecs A player's allowance of free respecs, meaning unlocks revoked to get the unlock point back
+type Respecs struct {
+	Player PlayerRef
+	// Allowance Number of free respecs granted to the player, nil if the default allowance applies
+	Allowance *uint16
+	Used      uint16
 }
 
-func DetermineAvailablePoints(p player.Player, unlockRecords []Record, awardRecords []award.Record) int {
-	usedPoints := 0
-	for _, record := range unlockRecords {
-		if record.Unlocked {
-			usedPoints++
-		}
+// Remaining Returns the number of free respecs the player has left
+func (r Respecs) Remaining(defaultAllowance uint16) uint16 {
+	allowance := defaultAllowance
+	if r.Allowance != nil {
+		allowance = *r.Allowance
 	}
-
-	// Player cannot have any unlock points available if they already unlocked everything
-	if usedPoints >= totalPossibleUnlocks {
+	if r.Used >= allowance {
 		return 0
 	}
+	return allowance - r.Used
+}
 
-	// No more than 7 unlocks via rank, but don't let the number go negative
-	rankPoints := max(min(int(p.Rank.ID)-1, 7), 0)
+type PlayerRef struct {
+	ID uint32
+}
 
-	// One point per level two badge
-	badgePoints := 0
-	for _, record := range awardRecords {
-		if record.Award.Type == award.TypeBadge && award.IsKitBadge(record.Award.ID) && record.Level == 2 {
-			badgePoints++
-		}
-	}
-	// Unless the data in the db is inconsistent, more than 7 points should never be seen
-	badgePoints = min(badgePoints, 7)
+// Points Unlock points available to a player, per pool
+type Points struct {
+	Enlisted int
+	Officer  int
+}
 
-	return max(rankPoints+badgePoints-usedPoints, 0)
+// Get Returns the points available in the given pool
+func (p Points) Get(kind PoolKind) int {
+	if kind == PoolKindOfficer {
+		return p.Officer
+	}
+	return p.Enlisted
 }
@@ -10,18 +10,6 @@ const (
 	GroundDefense uint8 = 6
 )
 
-var (
-	IDs = []uint8{
-		Armor,
-		Jet,
-		AirDefense,
-		Helicopter,
-		Transport,
-		Artillery,
-		GroundDefense,
-	}
-)
-
 type Record struct {
 	Player    PlayerRef
 	Vehicle   VehicleRef
@@ -21,29 +21,6 @@ const (
 	Tactical        uint8 = 17
 )
 
-var (
-	IDs = []uint8{
-		AssaultRifle,
-		AssaultGrenade,
-		Carbine,
-		LightMachineGun,
-		SniperRifle,
-		Pistol,
-		AntiTankAntiAir,
-		SubMachineGun,
-		Shotgun,
-		Knife,
-		Defibrillator,
-		C4,
-		HandGrenade,
-		Claymore,
-		AntiTankMine,
-		GrapplingHook,
-		Zipline,
-		Tactical,
-	}
-)
-
 type Weapon struct {
 	ID          uint8
 	Name        string
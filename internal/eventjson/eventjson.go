@@ -0,0 +1,133 @@
+package eventjson
+
+import (
+	"encoding/json"
+
+	"github.com/cetteup/gasp/internal/domain/event"
+)
+
+type payloadDTO struct {
+	Type      event.Type `json:"type"`
+	Timestamp uint32     `json:"timestamp"`
+	// Player Omitted for events not related to a single player
+	Player      *playerDTO      `json:"player,omitempty"`
+	Rank        *rankDTO        `json:"rank,omitempty"`
+	Award       *awardDTO       `json:"award,omitempty"`
+	Unlock      *unlockDTO      `json:"unlock,omitempty"`
+	Ban         *banDTO         `json:"ban,omitempty"`
+	Round       *roundDTO       `json:"round,omitempty"`
+	Leaderboard *leaderboardDTO `json:"leaderboard,omitempty"`
+}
+
+type playerDTO struct {
+	ID   uint32 `json:"id"`
+	Name string `json:"name"`
+}
+
+type rankDTO struct {
+	ID        uint8 `json:"id"`
+	Decreased bool  `json:"decreased"`
+}
+
+type awardDTO struct {
+	ID      uint32 `json:"id"`
+	Level   uint64 `json:"level"`
+	RoundID uint32 `json:"roundId"`
+}
+
+type unlockDTO struct {
+	ID uint16 `json:"id"`
+}
+
+type banDTO struct {
+	TimesBanned uint16 `json:"timesBanned"`
+	Permanent   bool   `json:"permanent"`
+}
+
+type roundDTO struct {
+	ID      uint32   `json:"id"`
+	Server  uint32   `json:"server"`
+	Map     uint16   `json:"map"`
+	Players []uint32 `json:"players"`
+}
+
+type leaderboardDTO struct {
+	Entries []entryDTO `json:"entries"`
+}
+
+type entryDTO struct {
+	Position uint32    `json:"position"`
+	Player   playerDTO `json:"player"`
+	Score    int64     `json:"score"`
+}
+
+// Marshal Encodes the event as it is sent to webhooks and event stream subscribers
+func Marshal(e event.Event) ([]byte, error) {
+	return json.Marshal(toPayloadDTO(e))
+}
+
+func toPayloadDTO(e event.Event) payloadDTO {
+	dto := payloadDTO{
+		Type:      e.Type,
+		Timestamp: e.Timestamp,
+	}
+
+	if e.Player.ID != 0 {
+		dto.Player = &playerDTO{
+			ID:   e.Player.ID,
+			Name: e.Player.Name,
+		}
+	}
+
+	if e.Rank != nil {
+		dto.Rank = &rankDTO{
+			ID:        e.Rank.ID,
+			Decreased: e.Rank.Decreased,
+		}
+	}
+	if e.Award != nil {
+		dto.Award = &awardDTO{
+			ID:      e.Award.ID,
+			Level:   e.Award.Level,
+			RoundID: e.Award.RoundID,
+		}
+	}
+	if e.Unlock != nil {
+		dto.Unlock = &unlockDTO{
+			ID: e.Unlock.ID,
+		}
+	}
+	if e.Ban != nil {
+		dto.Ban = &banDTO{
+			TimesBanned: e.Ban.TimesBanned,
+			Permanent:   e.Ban.Permanent,
+		}
+	}
+
+	if e.Round != nil {
+		dto.Round = &roundDTO{
+			ID:      e.Round.ID,
+			Server:  e.Round.ServerID,
+			Map:     e.Round.FieldID,
+			Players: e.Round.PlayerIDs,
+		}
+	}
+	if e.Leaderboard != nil {
+		entries := make([]entryDTO, 0, len(e.Leaderboard.Entries))
+		for _, entry := range e.Leaderboard.Entries {
+			entries = append(entries, entryDTO{
+				Position: entry.Position,
+				Player: playerDTO{
+					ID:   entry.Player.ID,
+					Name: entry.Player.Name,
+				},
+				Score: entry.Score,
+			})
+		}
+		dto.Leaderboard = &leaderboardDTO{
+			Entries: entries,
+		}
+	}
+
+	return dto
+}
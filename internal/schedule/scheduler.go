@@ -0,0 +1,203 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog/log"
+
+	"github.com/cetteup/gasp/internal/domain/job"
+	"github.com/cetteup/gasp/pkg/task"
+)
+
+type Job struct {
+	Name string
+	// Spec Standard cron spec (e.g. "0 3 * * 1") or descriptor (e.g. "@weekly", "@every 5m")
+	Spec string
+	Task task.Task
+	// RunOnStart Always run the job on start, regardless of the persisted state (e.g. for jobs populating memory)
+	RunOnStart bool
+}
+
+type Status struct {
+	job.State
+	Spec    string
+	Running bool
+}
+
+type Scheduler struct {
+	repository job.Repository
+	entries    []*entry
+}
+
+type entry struct {
+	job      Job
+	schedule cron.Schedule
+	running  atomic.Bool
+
+	mu    sync.Mutex
+	state job.State
+}
+
+func NewScheduler(repository job.Repository) *Scheduler {
+	return &Scheduler{
+		repository: repository,
+	}
+}
+
+// Add Adds a job to the scheduler, must not be called after Run
+func (s *Scheduler) Add(j Job) error {
+	schedule, err := cron.ParseStandard(j.Spec)
+	if err != nil {
+		return fmt.Errorf("invalid spec for job %s: %w", j.Name, err)
+	}
+
+	s.entries = append(s.entries, &entry{
+		job:      j,
+		schedule: schedule,
+		state: job.State{
+			Name: j.Name,
+		},
+	})
+
+	return nil
+}
+
+// Run Runs all jobs according to their schedule until ctx is cancelled, then waits for any running jobs to return
+func (s *Scheduler) Run(ctx context.Context) {
+	states, err := s.repository.FindAll(ctx)
+	if err != nil {
+		// Not being able to restore the state should not keep the jobs from running
+		log.Ctx(ctx).Error().
+			Err(err).
+			Msg("Failed to find persisted job states")
+	}
+
+	persisted := make(map[string]job.State, len(states))
+	for _, state := range states {
+		persisted[state.Name] = state
+	}
+
+	var wg sync.WaitGroup
+	for _, e := range s.entries {
+		if state, ok := persisted[e.job.Name]; ok {
+			e.state = state
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, e)
+		}()
+	}
+	wg.Wait()
+}
+
+// Statuses Returns the current status of every job
+func (s *Scheduler) Statuses() []Status {
+	statuses := make([]Status, 0, len(s.entries))
+	for _, e := range s.entries {
+		e.mu.Lock()
+		statuses = append(statuses, Status{
+			State:   e.state,
+			Spec:    e.job.Spec,
+			Running: e.running.Load(),
+		})
+		e.mu.Unlock()
+	}
+	return statuses
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	next := s.first(e)
+	for {
+		e.mu.Lock()
+		// Will overflow on 7 February 2106 at 06:28:15 UTC
+		e.state.NextRun = uint32(next.Unix())
+		e.mu.Unlock()
+		s.persist(ctx, e)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.execute(ctx, e)
+		next = e.schedule.Next(time.Now())
+	}
+}
+
+// first Determines the first run of the job, catching up on any run missed while the process was not running
+func (s *Scheduler) first(e *entry) time.Time {
+	now := time.Now()
+	if e.job.RunOnStart || e.state.LastRun == 0 {
+		return now
+	}
+
+	next := e.schedule.Next(time.Unix(int64(e.state.LastRun), 0))
+	if next.Before(now) {
+		return now
+	}
+	return next
+}
+
+func (s *Scheduler) execute(ctx context.Context, e *entry) {
+	// Never run the same job more than once at a time
+	if !e.running.CompareAndSwap(false, true) {
+		log.Ctx(ctx).Warn().
+			Str("job", e.job.Name).
+			Msg("Skipping job run, previous run is still in progress")
+		return
+	}
+	defer e.running.Store(false)
+
+	start := time.Now()
+	err := e.job.Task(ctx)
+	duration := time.Since(start)
+
+	e.mu.Lock()
+	// Will overflow on 7 February 2106 at 06:28:15 UTC
+	e.state.LastRun = uint32(start.Unix())
+	e.state.LastDuration = uint32(duration.Milliseconds())
+	e.state.LastError = ""
+	if err != nil {
+		e.state.LastError = err.Error()
+	}
+	e.mu.Unlock()
+
+	if err != nil {
+		log.Ctx(ctx).Error().
+			Err(err).
+			Str("job", e.job.Name).
+			Str("duration", duration.Truncate(time.Millisecond).String()).
+			Msg("Job failed")
+	} else {
+		log.Ctx(ctx).Info().
+			Str("job", e.job.Name).
+			Str("duration", duration.Truncate(time.Millisecond).String()).
+			Msg("Job completed")
+	}
+
+	s.persist(ctx, e)
+}
+
+func (s *Scheduler) persist(ctx context.Context, e *entry) {
+	e.mu.Lock()
+	state := e.state
+	e.mu.Unlock()
+
+	// Persist state even if ctx was cancelled due to shutdown
+	if err := s.repository.Save(context.WithoutCancel(ctx), state); err != nil {
+		log.Ctx(ctx).Error().
+			Err(err).
+			Str("job", e.job.Name).
+			Msg("Failed to persist job state")
+	}
+}
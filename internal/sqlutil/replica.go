@@ -0,0 +1,200 @@
+package sqlutil
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type Replica struct {
+	// Name Identifies the replica in logs (e.g. its host)
+	Name string
+	DB   *sql.DB
+}
+
+// ReplicaSet Runs statements and transactions on the primary, while spreading queries across the replicas. Replicas are
+// only queried while healthy (replicating with no more than the allowed lag), queries are run on the primary if none
+// of the replicas are healthy. Replicas start out as unhealthy until checked (see Run).
+type ReplicaSet struct {
+	*sql.DB
+	replicas []*replicaState
+	maxLag   time.Duration
+	next     atomic.Uint64
+}
+
+type replicaState struct {
+	Replica
+	healthy atomic.Bool
+}
+
+func NewReplicaSet(primary *sql.DB, replicas []Replica, maxLag time.Duration) *ReplicaSet {
+	states := make([]*replicaState, 0, len(replicas))
+	for _, replica := range replicas {
+		states = append(states, &replicaState{Replica: replica})
+	}
+
+	return &ReplicaSet{
+		DB:       primary,
+		replicas: states,
+		maxLag:   maxLag,
+	}
+}
+
+func (s *ReplicaSet) Query(query string, args ...any) (*sql.Rows, error) {
+	return s.choose().Query(query, args...)
+}
+
+func (s *ReplicaSet) QueryRow(query string, args ...any) *sql.Row {
+	return s.choose().QueryRow(query, args...)
+}
+
+func (s *ReplicaSet) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return s.choose().QueryContext(ctx, query, args...)
+}
+
+func (s *ReplicaSet) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return s.choose().QueryRowContext(ctx, query, args...)
+}
+
+// Run Checks the replicas' health immediately and then on every interval until the context is cancelled
+func (s *ReplicaSet) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, replica := range s.replicas {
+			s.check(ctx, replica, interval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close Closes the connections to the replicas, but not to the primary
+func (s *ReplicaSet) Close() error {
+	var errs []error
+	for _, replica := range s.replicas {
+		if err := replica.DB.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *ReplicaSet) choose() *sql.DB {
+	// Start at the next replica on every call to spread queries evenly across healthy replicas
+	offset := s.next.Add(1)
+	for i := range s.replicas {
+		replica := s.replicas[(offset+uint64(i))%uint64(len(s.replicas))]
+		if replica.healthy.Load() {
+			return replica.DB
+		}
+	}
+
+	return s.DB
+}
+
+func (s *ReplicaSet) check(ctx context.Context, replica *replicaState, timeout time.Duration) {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	lag, err := replicationLag(checkCtx, replica.DB)
+	if err == nil && lag > s.maxLag {
+		err = fmt.Errorf("lag of %s exceeds maximum of %s", lag, s.maxLag)
+	}
+	// Don't mark replicas as unhealthy because the check was cancelled (e.g. on shutdown)
+	if ctx.Err() != nil {
+		return
+	}
+
+	healthy := err == nil
+	if replica.healthy.Swap(healthy) == healthy {
+		return
+	}
+
+	if healthy {
+		log.Ctx(ctx).Info().
+			Str("replica", replica.Name).
+			Dur("lag", lag).
+			Msg("Replica is healthy, sending queries to replica")
+	} else {
+		log.Ctx(ctx).Warn().
+			Err(err).
+			Str("replica", replica.Name).
+			Msg("Replica is unhealthy, no longer sending queries to replica")
+	}
+}
+
+// replicationLag Returns how far the replica is behind its source (the largest lag of any channel if the replica has
+// multiple sources). Requires the REPLICATION CLIENT privilege.
+func replicationLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	rows, err := db.QueryContext(ctx, "SHOW REPLICA STATUS")
+	if err != nil {
+		// Older MySQL and MariaDB versions only support the legacy statement
+		var err2 error
+		rows, err2 = db.QueryContext(ctx, "SHOW SLAVE STATUS")
+		if err2 != nil {
+			return 0, fmt.Errorf("failed to query replica status: %w", err)
+		}
+	}
+	// Status contains dozens of columns, only one of which is relevant, so rows may not be read to the end
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read replica status columns: %w", err)
+	}
+
+	// Column was renamed in MySQL 8.0.22 (MariaDB still uses the original name)
+	index := slices.IndexFunc(columns, func(column string) bool {
+		return column == "Seconds_Behind_Source" || column == "Seconds_Behind_Master"
+	})
+	if index == -1 {
+		return 0, errors.New("replica status does not contain lag")
+	}
+
+	var seconds sql.NullInt64
+	dest := make([]any, len(columns))
+	for i := range dest {
+		dest[i] = new(sql.RawBytes)
+	}
+	dest[index] = &seconds
+
+	var lag time.Duration
+	var found bool
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			return 0, fmt.Errorf("failed to scan replica status: %w", err)
+		}
+
+		// Lag is NULL if replication is not running
+		if !seconds.Valid {
+			return 0, errors.New("replication is not running")
+		}
+
+		lag = max(lag, time.Duration(seconds.Int64)*time.Second)
+		found = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read replica status: %w", err)
+	}
+
+	// Status is empty if the server is not a replica at all
+	if !found {
+		return 0, errors.New("not configured as a replica")
+	}
+
+	return lag, nil
+}
@@ -1,14 +1,29 @@
 package sqlutil
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/go-sql-driver/mysql"
 )
 
+// TxRunner Runner which can also begin transactions, e.g. *sql.DB
+type TxRunner interface {
+	sq.BaseRunner
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// DB Handle to the live database, e.g. *sql.DB or a ReplicaSet
+type DB interface {
+	sq.StdSqlCtx
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
 func Connect(host, dbname, user, passwd string) *sql.DB {
 	cfg := mysql.Config{
 		User:                 user,
@@ -35,6 +50,20 @@ func Connect(host, dbname, user, passwd string) *sql.DB {
 	return db
 }
 
+// IsDuplicateEntry Returns whether err is caused by a duplicate value for a primary or unique key
+func IsDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	// ER_DUP_ENTRY, see https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// IsMissingTable Returns whether err is caused by a table not existing (e.g. because a migration has not been applied)
+func IsMissingTable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	// ER_NO_SUCH_TABLE, see https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1146
+}
+
 func EscapeWildcards(s string) string {
 	r := strings.NewReplacer(
 		"%", "\\%",
@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/cetteup/gasp/internal/domain/event"
+)
+
+const (
+	batchSize = 100
+)
+
+type DispatcherOptions struct {
+	// MaxAttempts Number of attempts after which a delivery is given up on
+	MaxAttempts uint16
+	// Backoff Delay before the second attempt, which doubles with every further attempt
+	Backoff time.Duration
+	// MaxBackoff Upper bound for the delay between attempts
+	MaxBackoff time.Duration
+	// Timeout Time to wait for a webhook to respond
+	Timeout time.Duration
+}
+
+// Dispatcher Delivers queued events to their webhooks, retrying failed deliveries with exponential backoff
+type Dispatcher struct {
+	repository event.OutboxRepository
+	webhooks   map[string]Webhook
+	client     *http.Client
+	opts       DispatcherOptions
+}
+
+func NewDispatcher(repository event.OutboxRepository, webhooks []Webhook, opts DispatcherOptions) *Dispatcher {
+	byName := make(map[string]Webhook, len(webhooks))
+	for _, w := range webhooks {
+		byName[w.Name] = w
+	}
+
+	return &Dispatcher{
+		repository: repository,
+		webhooks:   byName,
+		client: &http.Client{
+			Timeout: opts.Timeout,
+		},
+		opts: opts,
+	}
+}
+
+// Run Attempts all due deliveries, failed deliveries are rescheduled rather than causing the run to fail
+func (d *Dispatcher) Run(ctx context.Context) error {
+	for {
+		// Will overflow on 7 February 2106 at 06:28:15 UTC
+		deliveries, err := d.repository.FindDue(ctx, uint32(time.Now().UTC().Unix()), batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to find due deliveries: %w", err)
+		}
+
+		for _, delivery := range deliveries {
+			if err = d.attempt(ctx, delivery); err != nil {
+				return err
+			}
+		}
+
+		// Fewer deliveries than requested means there are no more due deliveries
+		if len(deliveries) < batchSize || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, delivery event.Delivery) error {
+	now := time.Now().UTC()
+	attempts := delivery.Attempts + 1
+
+	w, ok := d.webhooks[delivery.Webhook]
+	if !ok {
+		// Webhook was removed from the config, so there is nowhere to deliver to
+		if err := d.repository.MarkFailed(ctx, delivery.ID, delivery.Attempts, 0, "webhook not configured"); err != nil {
+			return fmt.Errorf("failed to mark delivery %d as failed: %w", delivery.ID, err)
+		}
+		return nil
+	}
+
+	// Will overflow on 7 February 2106 at 06:28:15 UTC
+	timestamp := uint32(now.Unix())
+	err := d.send(ctx, w, delivery, timestamp)
+	if err == nil {
+		if err = d.repository.MarkDelivered(ctx, delivery.ID, timestamp); err != nil {
+			return fmt.Errorf("failed to mark delivery %d as delivered: %w", delivery.ID, err)
+		}
+		return nil
+	}
+
+	// Zero next attempt means giving up
+	var next uint32
+	if attempts < d.opts.MaxAttempts {
+		// Will overflow on 7 February 2106 at 06:28:15 UTC
+		next = uint32(now.Add(d.backoff(attempts)).Unix())
+	}
+
+	log.Ctx(ctx).Warn().
+		Err(err).
+		Str("webhook", w.Name).
+		Uint64("delivery", delivery.ID).
+		Uint16("attempts", attempts).
+		Bool("retry", next != 0).
+		Msg("Failed to deliver event")
+
+	if err = d.repository.MarkFailed(ctx, delivery.ID, attempts, next, err.Error()); err != nil {
+		return fmt.Errorf("failed to mark delivery %d as failed: %w", delivery.ID, err)
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) send(ctx context.Context, w Webhook, delivery event.Delivery, timestamp uint32) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(delivery.Type))
+	// Delivery id lets receivers detect duplicates, since deliveries are retried if the response is lost
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatUint(uint64(timestamp), 10))
+	if w.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(w.Secret, timestamp, delivery.Payload))
+	}
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Drain the body to allow re-use of the connection
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// backoff Returns the delay before the next attempt, given the number of failed attempts
+func (d *Dispatcher) backoff(attempts uint16) time.Duration {
+	delay := d.opts.Backoff
+	for i := uint16(1); i < attempts && delay < d.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.opts.MaxBackoff)
+}
@@ -0,0 +1,241 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cetteup/gasp/internal/domain/event"
+)
+
+type failedDelivery struct {
+	id          uint64
+	attempts    uint16
+	nextAttempt uint32
+	reason      string
+}
+
+// outbox In-memory event.OutboxRepository returning the pending deliveries once
+type outbox struct {
+	pending   []event.Delivery
+	enqueued  []event.Delivery
+	delivered map[uint64]uint32
+	failed    []failedDelivery
+}
+
+func (o *outbox) Enqueue(_ context.Context, deliveries []event.Delivery) error {
+	o.enqueued = append(o.enqueued, deliveries...)
+	return nil
+}
+
+func (o *outbox) FindDue(_ context.Context, _ uint32, _ uint32) ([]event.Delivery, error) {
+	due := o.pending
+	o.pending = nil
+	return due, nil
+}
+
+func (o *outbox) MarkDelivered(_ context.Context, id uint64, timestamp uint32) error {
+	if o.delivered == nil {
+		o.delivered = make(map[uint64]uint32)
+	}
+	o.delivered[id] = timestamp
+	return nil
+}
+
+func (o *outbox) MarkFailed(_ context.Context, id uint64, attempts uint16, nextAttempt uint32, reason string) error {
+	o.failed = append(o.failed, failedDelivery{
+		id:          id,
+		attempts:    attempts,
+		nextAttempt: nextAttempt,
+		reason:      reason,
+	})
+	return nil
+}
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver Returns a server responding with the given status code and recording every request it receives
+func newReceiver(t *testing.T, status int) (*httptest.Server, func() []receivedRequest) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var received []receivedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read request body: %v", err)
+		}
+
+		mu.Lock()
+		received = append(received, receivedRequest{header: r.Header.Clone(), body: body})
+		mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []receivedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+}
+
+func newDispatcher(repository event.OutboxRepository, webhooks ...Webhook) *Dispatcher {
+	return NewDispatcher(repository, webhooks, DispatcherOptions{
+		MaxAttempts: 5,
+		Backoff:     time.Minute,
+		MaxBackoff:  5 * time.Minute,
+		Timeout:     time.Second,
+	})
+}
+
+func TestDispatcher_Run_SignsPayload(t *testing.T) {
+	server, received := newReceiver(t, http.StatusNoContent)
+	payload := []byte(`{"type":"rank.changed"}`)
+	repository := &outbox{
+		pending: []event.Delivery{
+			{ID: 1, Webhook: "signed", Type: event.TypeRankChanged, Payload: payload},
+			{ID: 2, Webhook: "unsigned", Type: event.TypeRankChanged, Payload: payload},
+		},
+	}
+	d := newDispatcher(
+		repository,
+		Webhook{Name: "signed", URL: server.URL, Secret: "secret"},
+		Webhook{Name: "unsigned", URL: server.URL},
+	)
+
+	if err := d.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	requests := received()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+
+	signed := requests[0]
+	if got := signed.header.Get(HeaderDelivery); got != "1" {
+		t.Errorf("expected delivery header 1, got %q", got)
+	}
+	if got := signed.header.Get(HeaderEvent); got != string(event.TypeRankChanged) {
+		t.Errorf("expected event header %q, got %q", event.TypeRankChanged, got)
+	}
+	if string(signed.body) != string(payload) {
+		t.Errorf("expected payload %s, got %s", payload, signed.body)
+	}
+	timestamp, err := strconv.ParseUint(signed.header.Get(HeaderTimestamp), 10, 32)
+	if err != nil {
+		t.Fatalf("invalid timestamp header: %v", err)
+	}
+	if want, got := Sign("secret", uint32(timestamp), signed.body), signed.header.Get(HeaderSignature); got != want {
+		t.Errorf("expected signature %q, got %q", want, got)
+	}
+
+	if got := requests[1].header.Get(HeaderSignature); got != "" {
+		t.Errorf("expected no signature without a secret, got %q", got)
+	}
+
+	if got, ok := repository.delivered[1]; !ok || got != uint32(timestamp) {
+		t.Errorf("expected delivery 1 to be marked delivered at %d, got %d (marked: %t)", timestamp, got, ok)
+	}
+	if len(repository.failed) != 0 {
+		t.Errorf("expected no failed deliveries, got %v", repository.failed)
+	}
+}
+
+func TestDispatcher_Run_Backoff(t *testing.T) {
+	tests := []struct {
+		name          string
+		attempts      uint16
+		wantAttempts  uint16
+		wantBackoff   time.Duration
+		wantGivenUpOn bool
+	}{
+		{name: "first attempt", attempts: 0, wantAttempts: 1, wantBackoff: time.Minute},
+		{name: "second attempt", attempts: 1, wantAttempts: 2, wantBackoff: 2 * time.Minute},
+		{name: "third attempt", attempts: 2, wantAttempts: 3, wantBackoff: 4 * time.Minute},
+		{name: "capped at max backoff", attempts: 3, wantAttempts: 4, wantBackoff: 5 * time.Minute},
+		{name: "gives up after max attempts", attempts: 4, wantAttempts: 5, wantGivenUpOn: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, received := newReceiver(t, http.StatusInternalServerError)
+			repository := &outbox{
+				pending: []event.Delivery{
+					{ID: 7, Webhook: "test", Type: event.TypeAwardEarned, Attempts: tt.attempts},
+				},
+			}
+			d := newDispatcher(repository, Webhook{Name: "test", URL: server.URL})
+
+			before := time.Now().UTC().Unix()
+			if err := d.Run(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			after := time.Now().UTC().Unix()
+
+			if len(received()) != 1 {
+				t.Fatalf("expected 1 request, got %d", len(received()))
+			}
+			if len(repository.delivered) != 0 {
+				t.Errorf("expected no delivered deliveries, got %v", repository.delivered)
+			}
+			if len(repository.failed) != 1 {
+				t.Fatalf("expected 1 failed delivery, got %d", len(repository.failed))
+			}
+
+			failed := repository.failed[0]
+			if failed.id != 7 {
+				t.Errorf("expected delivery 7 to be marked failed, got %d", failed.id)
+			}
+			if failed.attempts != tt.wantAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.wantAttempts, failed.attempts)
+			}
+			if tt.wantGivenUpOn {
+				if failed.nextAttempt != 0 {
+					t.Errorf("expected delivery to be given up on, got next attempt at %d", failed.nextAttempt)
+				}
+				return
+			}
+
+			earliest := before + int64(tt.wantBackoff.Seconds())
+			latest := after + int64(tt.wantBackoff.Seconds())
+			if next := int64(failed.nextAttempt); next < earliest || next > latest {
+				t.Errorf("expected next attempt between %d and %d, got %d", earliest, latest, next)
+			}
+		})
+	}
+}
+
+func TestDispatcher_Run_UnconfiguredWebhook(t *testing.T) {
+	server, received := newReceiver(t, http.StatusNoContent)
+	repository := &outbox{
+		pending: []event.Delivery{
+			{ID: 3, Webhook: "removed", Type: event.TypePlayerBanned, Attempts: 2},
+		},
+	}
+	d := newDispatcher(repository, Webhook{Name: "test", URL: server.URL})
+
+	if err := d.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(received()) != 0 {
+		t.Errorf("expected no requests, got %d", len(received()))
+	}
+	if len(repository.failed) != 1 {
+		t.Fatalf("expected 1 failed delivery, got %d", len(repository.failed))
+	}
+	want := failedDelivery{id: 3, attempts: 2, nextAttempt: 0, reason: "webhook not configured"}
+	if got := repository.failed[0]; got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cetteup/gasp/internal/domain/event"
+	"github.com/cetteup/gasp/internal/eventjson"
+)
+
+// Publisher Queues events for delivery to every webhook accepting them
+type Publisher struct {
+	repository event.OutboxRepository
+	webhooks   []Webhook
+}
+
+func NewPublisher(repository event.OutboxRepository, webhooks []Webhook) *Publisher {
+	return &Publisher{
+		repository: repository,
+		webhooks:   webhooks,
+	}
+}
+
+func (p *Publisher) Publish(ctx context.Context, events ...event.Event) error {
+	deliveries := make([]event.Delivery, 0, len(events))
+	for _, e := range events {
+		var payload []byte
+		for _, w := range p.webhooks {
+			if !w.Accepts(e.Type) {
+				continue
+			}
+
+			// Only encode events which are actually delivered anywhere
+			if payload == nil {
+				var err error
+				payload, err = eventjson.Marshal(e)
+				if err != nil {
+					return fmt.Errorf("failed to encode %s event: %w", e.Type, err)
+				}
+			}
+
+			deliveries = append(deliveries, event.Delivery{
+				Webhook: w.Name,
+				Type:    e.Type,
+				Payload: payload,
+				Created: e.Timestamp,
+			})
+		}
+	}
+
+	return p.repository.Enqueue(ctx, deliveries)
+}
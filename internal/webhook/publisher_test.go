@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cetteup/gasp/internal/domain/event"
+	"github.com/cetteup/gasp/internal/eventjson"
+)
+
+func TestPublisher_Publish(t *testing.T) {
+	rank := event.Event{
+		Type:      event.TypeRankChanged,
+		Player:    event.PlayerRef{ID: 42, Name: "player"},
+		Timestamp: 1700000000,
+		Rank:      &event.RankChange{ID: 5},
+	}
+	ban := event.Event{
+		Type:      event.TypePlayerBanned,
+		Player:    event.PlayerRef{ID: 43, Name: "cheater"},
+		Timestamp: 1700000001,
+		Ban:       &event.Ban{TimesBanned: 1, Permanent: true},
+	}
+
+	repository := &outbox{}
+	p := NewPublisher(repository, []Webhook{
+		{Name: "all"},
+		{Name: "bans", Events: []event.Type{event.TypePlayerBanned}},
+	})
+
+	if err := p.Publish(context.Background(), rank, ban); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		webhook string
+		event   event.Event
+	}{
+		{webhook: "all", event: rank},
+		{webhook: "all", event: ban},
+		{webhook: "bans", event: ban},
+	}
+	if len(repository.enqueued) != len(want) {
+		t.Fatalf("expected %d deliveries, got %d", len(want), len(repository.enqueued))
+	}
+	for i, w := range want {
+		got := repository.enqueued[i]
+		if got.Webhook != w.webhook || got.Type != w.event.Type || got.Created != w.event.Timestamp {
+			t.Errorf("delivery %d: expected %s event for %s created at %d, got %s event for %s created at %d",
+				i, w.event.Type, w.webhook, w.event.Timestamp, got.Type, got.Webhook, got.Created)
+		}
+
+		payload, err := eventjson.Marshal(w.event)
+		if err != nil {
+			t.Fatalf("failed to encode event: %v", err)
+		}
+		if string(got.Payload) != string(payload) {
+			t.Errorf("delivery %d: expected payload %s, got %s", i, payload, got.Payload)
+		}
+	}
+}
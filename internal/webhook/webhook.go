@@ -0,0 +1,45 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+
+	"github.com/cetteup/gasp/internal/domain/event"
+)
+
+const (
+	HeaderEvent     = "X-Gasp-Event"
+	HeaderDelivery  = "X-Gasp-Delivery"
+	HeaderTimestamp = "X-Gasp-Timestamp"
+	HeaderSignature = "X-Gasp-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+type Webhook struct {
+	// Name Identifies the webhook in the outbox, so it must not change while deliveries are pending
+	Name string
+	URL  string
+	// Secret Key to sign payloads with, payloads are not signed if empty
+	Secret string
+	// Events Types of events to deliver, all types if empty
+	Events []event.Type
+}
+
+func (w Webhook) Accepts(t event.Type) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, t)
+}
+
+// Sign Returns the signature of the payload sent at the given timestamp. Receivers should compute the HMAC-SHA256 of
+// "<timestamp>.<payload>" using the shared secret and compare it to the signature header (minus the "sha256=" prefix).
+// Including the timestamp allows receivers to reject replayed deliveries.
+func Sign(secret string, timestamp uint32, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatUint(uint64(timestamp), 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,27 @@
+-- Registry of game servers allowed to call protected (data writing) endpoints. Databases derived from the original
+-- backend (BF2Statistics) already contain a server table (referenced by round.server_id), which is extended rather
+-- than replaced. Columns are added without IF NOT EXISTS, so the script fails instead of silently leaving the table
+-- without them.
+
+CREATE TABLE IF NOT EXISTS `server`
+(
+    `id`   INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `name` VARCHAR(100) NOT NULL,
+    PRIMARY KEY (`id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `server`
+    -- Shared secret servers need to send in the X-Server-Key header, servers are authorized by address only if empty
+    ADD COLUMN `auth_key` VARCHAR(64)      NOT NULL DEFAULT '',
+    ADD COLUMN `ranked`   TINYINT UNSIGNED NOT NULL DEFAULT 0;
+
+-- No foreign key on server_id, since the type of the original schema's server id differs between versions
+CREATE TABLE IF NOT EXISTS `server_address`
+(
+    `server_id` INT UNSIGNED NOT NULL,
+    -- Single IP address or network in CIDR notation
+    `address`   VARCHAR(50)  NOT NULL,
+    PRIMARY KEY (`server_id`, `address`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,28 @@
+-- Per-round player records (deltas), from which the rising star table is computed, and every rebuild of the rising
+-- star table by the risingstar job
+
+CREATE TABLE IF NOT EXISTS `player_round`
+(
+    `round_id`   INT UNSIGNED NOT NULL,
+    `player_id`  INT UNSIGNED NOT NULL,
+    `score`      INT          NOT NULL DEFAULT 0,
+    `cmdscore`   INT          NOT NULL DEFAULT 0,
+    `skillscore` INT          NOT NULL DEFAULT 0,
+    `teamscore`  INT          NOT NULL DEFAULT 0,
+    `kills`      INT UNSIGNED NOT NULL DEFAULT 0,
+    `deaths`     INT UNSIGNED NOT NULL DEFAULT 0,
+    `time`       INT UNSIGNED NOT NULL DEFAULT 0,
+    `cmdtime`    INT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (`round_id`, `player_id`),
+    KEY `player_round_player_id_idx` (`player_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `risingstar_update`
+(
+    `id`        INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `timestamp` INT UNSIGNED NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `risingstar_update_timestamp_idx` (`timestamp`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
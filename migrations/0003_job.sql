@@ -0,0 +1,15 @@
+-- State of the built-in scheduler's jobs, persisted across restarts
+
+CREATE TABLE IF NOT EXISTS `job`
+(
+    `name`          VARCHAR(50)  NOT NULL,
+    -- Start of the last run, 0 if the job never ran
+    `last_run`      INT UNSIGNED NOT NULL DEFAULT 0,
+    -- Duration of the last run in milliseconds
+    `last_duration` INT UNSIGNED NOT NULL DEFAULT 0,
+    -- Error returned by the last run, empty if the run succeeded
+    `last_error`    TEXT         NOT NULL,
+    `next_run`      INT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (`name`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,48 @@
+-- Per-round player records (deltas) by kit, vehicle and weapon, aggregated by the time-windowed leaderboards
+
+CREATE TABLE IF NOT EXISTS `player_round_kit`
+(
+    `round_id`  INT UNSIGNED     NOT NULL,
+    `player_id` INT UNSIGNED     NOT NULL,
+    `kit_id`    TINYINT UNSIGNED NOT NULL,
+    `time`      INT UNSIGNED     NOT NULL DEFAULT 0,
+    `score`     INT              NOT NULL DEFAULT 0,
+    `kills`     INT UNSIGNED     NOT NULL DEFAULT 0,
+    `deaths`    INT UNSIGNED     NOT NULL DEFAULT 0,
+    PRIMARY KEY (`round_id`, `player_id`, `kit_id`),
+    KEY `player_round_kit_kit_id_idx` (`kit_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `player_round_vehicle`
+(
+    `round_id`   INT UNSIGNED     NOT NULL,
+    `player_id`  INT UNSIGNED     NOT NULL,
+    `vehicle_id` TINYINT UNSIGNED NOT NULL,
+    `time`       INT UNSIGNED     NOT NULL DEFAULT 0,
+    `score`      INT              NOT NULL DEFAULT 0,
+    `kills`      INT UNSIGNED     NOT NULL DEFAULT 0,
+    `deaths`     INT UNSIGNED     NOT NULL DEFAULT 0,
+    `roadkills`  INT UNSIGNED     NOT NULL DEFAULT 0,
+    PRIMARY KEY (`round_id`, `player_id`, `vehicle_id`),
+    KEY `player_round_vehicle_vehicle_id_idx` (`vehicle_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `player_round_weapon`
+(
+    `round_id`  INT UNSIGNED     NOT NULL,
+    `player_id` INT UNSIGNED     NOT NULL,
+    -- Weapon id as used in the database (not the ASP's)
+    `weapon_id` TINYINT UNSIGNED NOT NULL,
+    `time`      INT UNSIGNED     NOT NULL DEFAULT 0,
+    `score`     INT              NOT NULL DEFAULT 0,
+    `kills`     INT UNSIGNED     NOT NULL DEFAULT 0,
+    `deaths`    INT UNSIGNED     NOT NULL DEFAULT 0,
+    `fired`     INT UNSIGNED     NOT NULL DEFAULT 0,
+    `hits`      INT UNSIGNED     NOT NULL DEFAULT 0,
+    `deployed`  INT UNSIGNED     NOT NULL DEFAULT 0,
+    PRIMARY KEY (`round_id`, `player_id`, `weapon_id`),
+    KEY `player_round_weapon_weapon_id_idx` (`weapon_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
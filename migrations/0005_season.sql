@@ -0,0 +1,20 @@
+-- Seasons, the running season being the one without an end
+--
+-- Ended seasons are archived into separate schemas named "<schemaprefix><season id>" (schemaprefix defaults to
+-- "<dbname>_season_"), which are created by gasp when starting a new season. Archive tables are copies (CREATE TABLE
+-- ... LIKE) of the stats tables, so they do not need to be migrated. The database user does need to be allowed to
+-- create and fill the archive schemas, e.g.
+--
+--   GRANT CREATE, DROP, SELECT, INSERT ON `bf2stats\_season\_%`.* TO 'gasp'@'%';
+
+CREATE TABLE IF NOT EXISTS `season`
+(
+    `id`    INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `name`  VARCHAR(100) NOT NULL,
+    `start` INT UNSIGNED NOT NULL,
+    -- 0 while the season is running
+    `end`   INT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    KEY `season_end_idx` (`end`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,17 @@
+-- Per-round history: teams of each round and the team each player played on (the round's map, server and start/end
+-- times are part of the original schema's round table)
+
+CREATE TABLE IF NOT EXISTS `round_team`
+(
+    `round_id` INT UNSIGNED      NOT NULL,
+    -- Team number as used by the game (1 or 2)
+    `team`     TINYINT UNSIGNED  NOT NULL,
+    `army_id`  TINYINT UNSIGNED  NOT NULL,
+    `tickets`  SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+    `winner`   TINYINT UNSIGNED  NOT NULL DEFAULT 0,
+    PRIMARY KEY (`round_id`, `team`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `player_round`
+    ADD COLUMN IF NOT EXISTS `team` TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER `player_id`;
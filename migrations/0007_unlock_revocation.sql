@@ -0,0 +1,26 @@
+-- Unlock revocation: revoked unlock records are moved from player_unlock to player_unlock_revoked (so the unlock can be
+-- selected again), free respecs are tracked per player
+
+CREATE TABLE IF NOT EXISTS `player_unlock_revoked`
+(
+    `id`        INT UNSIGNED      NOT NULL AUTO_INCREMENT,
+    `player_id` INT UNSIGNED      NOT NULL,
+    `unlock_id` SMALLINT UNSIGNED NOT NULL,
+    -- Time the unlock was selected at
+    `timestamp` INT UNSIGNED      NOT NULL,
+    -- Time the unlock was revoked at
+    `revoked`   INT UNSIGNED      NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `player_unlock_revoked_player_id_idx` (`player_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `player_respec`
+(
+    `player_id` INT UNSIGNED      NOT NULL,
+    -- Number of free respecs granted to the player, NULL if the configured default applies
+    `allowance` SMALLINT UNSIGNED NULL     DEFAULT NULL,
+    `used`      SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (`player_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
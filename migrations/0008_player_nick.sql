@@ -0,0 +1,6 @@
+-- Indexed lower case nick (name without the clan tag, which is separated from the nick by a space) for the player
+-- search. Season archives created before applying this need the same column to be searchable.
+
+ALTER TABLE `player`
+    ADD COLUMN `nick` VARCHAR(64) AS (LOWER(SUBSTRING_INDEX(`name`, ' ', -1))) VIRTUAL,
+    ADD INDEX `player_nick_idx` (`nick`);
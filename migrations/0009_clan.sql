@@ -0,0 +1,26 @@
+-- Clans and their members, each player being a member of at most one clan
+
+CREATE TABLE IF NOT EXISTS `clan`
+(
+    `id`      INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    -- Prefix members put in front of their nick (e.g. "=TAG=")
+    `tag`     VARCHAR(6)   NOT NULL,
+    `name`    VARCHAR(64)  NOT NULL,
+    `created` INT UNSIGNED NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `clan_tag_uq` (`tag`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `clan_member`
+(
+    `player_id` INT UNSIGNED     NOT NULL,
+    `clan_id`   INT UNSIGNED     NOT NULL,
+    -- 0 = member, 1 = officer, 2 = leader
+    `role`      TINYINT UNSIGNED NOT NULL DEFAULT 0,
+    `joined`    INT UNSIGNED     NOT NULL,
+    PRIMARY KEY (`player_id`),
+    KEY `clan_member_clan_id_idx` (`clan_id`),
+    CONSTRAINT `clan_member_clan_id_fk` FOREIGN KEY (`clan_id`) REFERENCES `clan` (`id`) ON DELETE CASCADE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,40 @@
+-- Webhook deliveries (outbox) and the state the eventwatch job compares players' current state against
+
+CREATE TABLE IF NOT EXISTS `event_outbox`
+(
+    `id`           BIGINT UNSIGNED   NOT NULL AUTO_INCREMENT,
+    -- Name of the webhook (as configured) to deliver the event to
+    `webhook`      VARCHAR(64)       NOT NULL,
+    `type`         VARCHAR(32)       NOT NULL,
+    -- Encoded event, exactly as it is to be delivered
+    `payload`      MEDIUMBLOB        NOT NULL,
+    `created`      INT UNSIGNED      NOT NULL,
+    `attempts`     SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+    -- 0 once the delivery has been given up on
+    `next_attempt` INT UNSIGNED      NOT NULL DEFAULT 0,
+    -- 0 until the event has been delivered
+    `delivered`    INT UNSIGNED      NOT NULL DEFAULT 0,
+    `last_error`   TEXT              NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `event_outbox_due_idx` (`delivered`, `next_attempt`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `event_player_state`
+(
+    `player_id` INT UNSIGNED     NOT NULL,
+    `rank_id`   TINYINT UNSIGNED NOT NULL DEFAULT 0,
+    `banned`    INT UNSIGNED     NOT NULL DEFAULT 0,
+    `permban`   TINYINT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (`player_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `event_watermark`
+(
+    `name`  VARCHAR(32)  NOT NULL,
+    -- Last id seen (e.g. round id for awards)
+    `value` INT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (`name`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;